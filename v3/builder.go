@@ -0,0 +1,185 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import "strconv"
+
+// A PathBuilder configures a single PathItem of a Document.
+type PathBuilder struct {
+	doc  *Document
+	path string
+}
+
+// Path returns a builder for the PathItem of the given path. The item and the paths are created on demand
+// and an existing item is kept, so that multiple verbs can be added to the same path.
+func (d *Document) Path(path string) *PathBuilder {
+	if d.Paths.Item(path) == nil {
+		d.Paths.Set(path, PathItem{})
+	}
+	return &PathBuilder{doc: d, path: path}
+}
+
+// Summary sets the summary of the path item.
+func (b *PathBuilder) Summary(summary string) *PathBuilder {
+	b.update(func(item *PathItem) { item.Summary = summary })
+	return b
+}
+
+// Description sets the description of the path item.
+func (b *PathBuilder) Description(description string) *PathBuilder {
+	b.update(func(item *PathItem) { item.Description = description })
+	return b
+}
+
+// Parameter appends a parameter, which is shared by all operations of the path item.
+func (b *PathBuilder) Parameter(p Parameter) *PathBuilder {
+	b.update(func(item *PathItem) { item.Parameters = append(item.Parameters, p) })
+	return b
+}
+
+// Get returns a builder for the GET operation, which is created on demand.
+func (b *PathBuilder) Get() *OperationBuilder {
+	return b.operation(func(item *PathItem) **Operation { return &item.Get })
+}
+
+// Post returns a builder for the POST operation, which is created on demand.
+func (b *PathBuilder) Post() *OperationBuilder {
+	return b.operation(func(item *PathItem) **Operation { return &item.Post })
+}
+
+// Put returns a builder for the PUT operation, which is created on demand.
+func (b *PathBuilder) Put() *OperationBuilder {
+	return b.operation(func(item *PathItem) **Operation { return &item.Put })
+}
+
+// Patch returns a builder for the PATCH operation, which is created on demand.
+func (b *PathBuilder) Patch() *OperationBuilder {
+	return b.operation(func(item *PathItem) **Operation { return &item.Patch })
+}
+
+// Delete returns a builder for the DELETE operation, which is created on demand.
+func (b *PathBuilder) Delete() *OperationBuilder {
+	return b.operation(func(item *PathItem) **Operation { return &item.Delete })
+}
+
+// update applies the modification to the item, which is stored by value.
+func (b *PathBuilder) update(f func(item *PathItem)) {
+	f(b.doc.Paths.Item(b.path))
+}
+
+// operation returns a builder for the selected operation of the item, which is created on demand.
+func (b *PathBuilder) operation(field func(item *PathItem) **Operation) *OperationBuilder {
+	var op *Operation
+	b.update(func(item *PathItem) {
+		ptr := field(item)
+		if *ptr == nil {
+			*ptr = &Operation{Responses: map[string]Response{}}
+		}
+		op = *ptr
+	})
+	return &OperationBuilder{path: b, op: op}
+}
+
+// An OperationBuilder configures a single Operation of a PathItem.
+type OperationBuilder struct {
+	path *PathBuilder
+	op   *Operation
+}
+
+// Summary sets the summary of the operation.
+func (b *OperationBuilder) Summary(summary string) *OperationBuilder {
+	b.op.Summary = summary
+	return b
+}
+
+// Description sets the description of the operation.
+func (b *OperationBuilder) Description(description string) *OperationBuilder {
+	b.op.Description = description
+	return b
+}
+
+// OperationId sets the unique id of the operation.
+func (b *OperationBuilder) OperationId(id string) *OperationBuilder {
+	b.op.OperationId = id
+	return b
+}
+
+// Tags appends the tags of the operation.
+func (b *OperationBuilder) Tags(tags ...string) *OperationBuilder {
+	b.op.Tags = append(b.op.Tags, tags...)
+	return b
+}
+
+// Deprecated marks the operation as deprecated.
+func (b *OperationBuilder) Deprecated() *OperationBuilder {
+	b.op.Deprecated = true
+	return b
+}
+
+// Parameter appends a parameter to the operation.
+func (b *OperationBuilder) Parameter(p Parameter) *OperationBuilder {
+	b.op.Parameters = append(b.op.Parameters, p)
+	return b
+}
+
+// RequestBody sets the request body of the operation.
+func (b *OperationBuilder) RequestBody(body RequestBody) *OperationBuilder {
+	b.op.RequestBody = &body
+	return b
+}
+
+// Response sets the response for the given http status code.
+func (b *OperationBuilder) Response(code int, resp Response) *OperationBuilder {
+	b.setResponse(strconv.Itoa(code), resp)
+	return b
+}
+
+// DefaultResponse sets the response for all undeclared status codes.
+func (b *OperationBuilder) DefaultResponse(resp Response) *OperationBuilder {
+	b.setResponse("default", resp)
+	return b
+}
+
+// setResponse sets the response of the key and creates the responses of an existing operation on demand.
+func (b *OperationBuilder) setResponse(key string, resp Response) {
+	if b.op.Responses == nil {
+		b.op.Responses = map[string]Response{}
+	}
+	b.op.Responses[key] = resp
+}
+
+// Path returns the builder of the path item, e.g. to continue with another verb.
+func (b *OperationBuilder) Path() *PathBuilder {
+	return b.path
+}
+
+// Operation returns the configured operation.
+func (b *OperationBuilder) Operation() *Operation {
+	return b.op
+}
+
+// JSONContent returns the content map of a RequestBody or Response, which declares the schema for
+// application/json.
+func JSONContent(schema Schema) map[string]MediaType {
+	return map[string]MediaType{MediaTypeJSON: {Schema: schema}}
+}
+
+// FormContent returns the content map of a RequestBody, which declares the schema for
+// application/x-www-form-urlencoded. The properties of the object schema are the form fields.
+func FormContent(schema Schema) map[string]MediaType {
+	return map[string]MediaType{MediaTypeFormURLEncoded: {Schema: schema}}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_builder(t *testing.T) {
+	id := Parameter{Name: "id", In: PathLocation, Required: true}
+	pet := Response{Description: "the pet"}
+
+	built := NewDocument()
+	built.Path("/pets/{id}").
+		Summary("A single pet").
+		Parameter(id).
+		Get().Summary("Get a pet").Tags("pets").Response(200, pet).
+		Path().
+		Delete().Summary("Delete a pet").Response(204, Response{Description: "deleted"})
+
+	// a second call must not clobber the existing item
+	built.Path("/pets/{id}").Get().OperationId("getPet")
+
+	expected := NewDocument()
+	expected.Paths.Set("/pets/{id}", PathItem{
+		Summary:    "A single pet",
+		Parameters: []Parameter{id},
+		Get: &Operation{
+			Summary:     "Get a pet",
+			OperationId: "getPet",
+			Tags:        []string{"pets"},
+			Responses:   map[string]Response{"200": pet},
+		},
+		Delete: &Operation{
+			Summary:   "Delete a pet",
+			Responses: map[string]Response{"204": {Description: "deleted"}},
+		},
+	})
+
+	if !reflect.DeepEqual(built, expected) {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, built)
+	}
+}
+
+func Test_builderExistingOperation(t *testing.T) {
+	doc := NewDocument()
+	doc.Paths.Set("/pets", PathItem{Get: &Operation{Summary: "list pets"}})
+	op := doc.Path("/pets").Get().
+		Response(200, Response{Description: "ok"}).
+		DefaultResponse(Response{Description: "error"}).
+		Operation()
+
+	if op.Summary != "list pets" || len(op.Responses) != 2 {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+}
+
+func Test_content(t *testing.T) {
+	pet := "#/components/schemas/Pet"
+	schema := Schema{Ref: &pet}
+
+	json := JSONContent(schema)
+	if len(json) != 1 || !reflect.DeepEqual(json["application/json"].Schema, schema) {
+		t.Fatalf("unexpected json content %v", json)
+	}
+
+	login := Schema{Type: Object, Properties: map[string]Schema{"user": {Type: String}, "password": {Type: String}}}
+	form := FormContent(login)
+	if len(form) != 1 || !reflect.DeepEqual(form["application/x-www-form-urlencoded"].Schema, login) {
+		t.Fatalf("unexpected form content %v", form)
+	}
+
+	op := NewDocument().Path("/login").
+		Post().
+		RequestBody(RequestBody{Required: true, Content: form}).
+		Response(200, Response{Description: "ok", Content: JSONContent(schema)}).
+		Operation()
+
+	if _, ok := op.RequestBody.Content["application/x-www-form-urlencoded"]; !ok {
+		t.Fatal("expected the form content in the request body")
+	}
+}
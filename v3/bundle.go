@@ -0,0 +1,226 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// invalidComponentChars matches all characters, which are not allowed in the name of a component.
+var invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// A bundler pulls external references into the components of a document.
+type bundler struct {
+	doc      *Document
+	main     string            // main is the cleaned path of the main document
+	resolver *ExternalResolver // resolver loads and caches the external files
+	names    map[string]string // names maps a canonical external reference to its local reference
+}
+
+// Bundle loads the main document and returns a self-contained document, where each external reference, like
+// ./schemas/pet.json or common.yaml#/components/schemas/Error, is copied into the components and replaced by a
+// local reference. References within an external file are resolved relative to that file. A component is named
+// after the last token of the fragment or otherwise after the file. If that name is already taken, the path of
+// the file relative to the main document is prepended and if that is still ambiguous, a counter is appended, so
+// the names only depend on the order of the references in the document. The files may be in the JSON or YAML
+// format.
+func Bundle(mainPath string, load func(path string) ([]byte, error)) (*Document, error) {
+	buf, err := load(mainPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load '%s': %w", mainPath, err)
+	}
+
+	doc, err := FromYaml(buf)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse '%s': %w", mainPath, err)
+	}
+
+	b := &bundler{doc: doc, main: path.Clean(mainPath), resolver: NewExternalResolver(load), names: map[string]string{}}
+	if err := b.bundle(reflect.ValueOf(doc).Elem(), b.main); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// bundle replaces the external references of the addressable model value, which has been declared in file.
+func (b *bundler) bundle(v reflect.Value, file string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return b.bundle(v.Elem(), file)
+	case reflect.Map:
+		for _, key := range sortedKeys(v.Interface()) {
+			k := reflect.ValueOf(key).Convert(v.Type().Key())
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(k))
+			if err := b.bundle(elem, file); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, elem)
+		}
+		return nil
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := b.bundle(v.Index(i), file); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		switch t := v.Addr().Interface().(type) {
+		case *URL:
+			return nil
+		case *Paths:
+			for _, path := range t.Keys() {
+				if err := b.bundle(reflect.ValueOf(t.Item(path)).Elem(), file); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if ref := refOf(v); ref != "" {
+			local, err := b.localRef(v.Type(), ref, file)
+			if err != nil {
+				return err
+			}
+			v.FieldByName("Ref").Set(reflect.ValueOf(&local))
+			return nil
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := b.bundle(v.Field(i), file); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// localRef returns the local reference of the component of type t, which is referenced by ref in file. An external
+// component is added to the components on first use.
+func (b *bundler) localRef(t reflect.Type, ref string, file string) (string, error) {
+	refFile, fragment := splitRef(ref)
+	switch {
+	case refFile == "" && file == b.main:
+		return ref, nil
+	case refFile == "":
+		refFile = file
+	default:
+		refFile = path.Join(path.Dir(file), refFile)
+	}
+
+	if refFile == b.main {
+		return "#" + fragment, nil
+	}
+
+	key := refFile + "#" + fragment
+	if local, ok := b.names[key]; ok {
+		return local, nil
+	}
+
+	components := b.components(t)
+	if !components.IsValid() {
+		return "", fmt.Errorf("cannot bundle '%s': a %s is not a component", ref, t.Name())
+	}
+
+	kind, _ := jsonFieldName(b.componentsField(t))
+	name := b.componentName(components, refFile, fragment)
+	local := pointerOf("components", kind, name)
+	b.names[key] = local
+
+	component := reflect.New(t)
+	if err := b.resolver.Resolve(key, component.Interface()); err != nil {
+		return "", err
+	}
+
+	// reserve the name before descending, so that cyclic references terminate
+	components.SetMapIndex(reflect.ValueOf(name), component.Elem())
+	if err := b.bundle(component.Elem(), refFile); err != nil {
+		return "", err
+	}
+	components.SetMapIndex(reflect.ValueOf(name), component.Elem())
+
+	return local, nil
+}
+
+// componentsField returns the field of the Components, which holds the components of type t.
+func (b *bundler) componentsField(t reflect.Type) reflect.StructField {
+	ct := reflect.TypeOf(Components{})
+	for i := 0; i < ct.NumField(); i++ {
+		if f := ct.Field(i); f.Type.Kind() == reflect.Map && f.Type.Elem() == t {
+			return f
+		}
+	}
+	return reflect.StructField{}
+}
+
+// components returns the settable map of the components of type t, which is created on demand.
+func (b *bundler) components(t reflect.Type) reflect.Value {
+	field := b.componentsField(t)
+	if field.Index == nil {
+		return reflect.Value{}
+	}
+
+	if b.doc.Components == nil {
+		b.doc.Components = &Components{}
+	}
+
+	m := reflect.ValueOf(b.doc.Components).Elem().FieldByIndex(field.Index)
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	return m
+}
+
+// componentName returns an unused name for the component, which is declared in file at the fragment.
+func (b *bundler) componentName(components reflect.Value, file, fragment string) string {
+	stem := strings.TrimSuffix(file, path.Ext(file))
+	base := path.Base(stem)
+	if tokens, err := parsePointer(fragment); err == nil && len(tokens) > 0 {
+		base = tokens[len(tokens)-1]
+	}
+
+	base = invalidComponentChars.ReplaceAllString(base, "_")
+	relative := strings.TrimPrefix(stem, path.Dir(b.main)+"/")
+	qualified := invalidComponentChars.ReplaceAllString(strings.TrimLeft(relative, "./"), "_")
+	if base != path.Base(stem) {
+		qualified += "_" + base
+	}
+
+	candidates := []string{base, qualified}
+	for i := 2; ; i++ {
+		for _, name := range candidates {
+			if !components.MapIndex(reflect.ValueOf(name)).IsValid() {
+				return name
+			}
+		}
+		candidates = []string{qualified + "_" + strconv.Itoa(i)}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_bundle(t *testing.T) {
+	files := map[string]string{
+		"api/openapi.yaml": `
+openapi: 3.0.3
+info:
+  title: pets
+  version: "1.0"
+paths:
+  /dogs:
+    get:
+      parameters:
+        - $ref: 'common.json#/parameters/limit'
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: 'schemas/dogs/pet.json'
+    post:
+      requestBody:
+        $ref: 'common.json#/requestBodies/dog'
+      responses:
+        201:
+          description: created
+  /cats:
+    get:
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: 'schemas/cats/pet.json'
+components:
+  schemas:
+    Error:
+      type: string
+`,
+		"api/common.json":           `{"Name": {"type": "string"}, "parameters": {"limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}}, "requestBodies": {"dog": {"required": true, "content": {"application/json": {"schema": {"type": "object"}}}}}}`,
+		"api/schemas/dogs/pet.json": `{"type": "object", "properties": {"name": {"$ref": "../../common.json#/Name"}, "error": {"$ref": "../../openapi.yaml#/components/schemas/Error"}}}`,
+		"api/schemas/cats/pet.json": `{"type": "object", "properties": {"tag": {"$ref": "#/definitions/Tag"}}, "definitions": {"Tag": {"type": "string"}}}`,
+	}
+
+	load := func(path string) ([]byte, error) {
+		if content, ok := files[path]; ok {
+			return []byte(content), nil
+		}
+		return nil, fmt.Errorf("no such file '%s'", path)
+	}
+
+	doc, err := Bundle("api/openapi.yaml", load)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"Error", "Name", "Tag", "pet", "schemas_cats_pet"}
+	if names := sortedKeys(doc.Components.Schemas); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v but got %v", expected, names)
+	}
+
+	refs := map[string]string{
+		"#/paths/~1dogs/get/responses/200/content/application~1json/schema": "#/components/schemas/pet",
+		"#/paths/~1cats/get/responses/200/content/application~1json/schema": "#/components/schemas/schemas_cats_pet",
+		"#/components/schemas/pet/properties/name":                          "#/components/schemas/Name",
+		"#/components/schemas/pet/properties/error":                         "#/components/schemas/Error",
+		"#/components/schemas/schemas_cats_pet/properties/tag":              "#/components/schemas/Tag",
+	}
+	for pointer, ref := range refs {
+		node, err := doc.ResolvePointer(pointer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if schema := node.(Schema); schema.Ref == nil || *schema.Ref != ref {
+			t.Fatalf("%s: expected %s but got %v", pointer, ref, schema.Ref)
+		}
+	}
+
+	if ref := doc.Paths.Item("/dogs").Get.Parameters[0].Ref; ref == nil || *ref != "#/components/parameters/limit" {
+		t.Fatalf("unexpected parameter reference %v", ref)
+	}
+
+	if limit := doc.Components.Parameters["limit"]; limit.Name != "limit" || limit.Schema.Type != Integer {
+		t.Fatalf("unexpected parameter component %+v", limit)
+	}
+
+	if ref := doc.Paths.Item("/dogs").Post.RequestBody.Ref; ref == nil || *ref != "#/components/requestBodies/dog" {
+		t.Fatalf("unexpected request body reference %v", ref)
+	}
+
+	if dog := doc.Components.RequestBodies["dog"]; !dog.Required || len(dog.Content) != 1 {
+		t.Fatalf("unexpected request body component %+v", dog)
+	}
+
+	again, err := Bundle("api/openapi.yaml", load)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(doc, again) {
+		t.Fatal("expected a deterministic bundle")
+	}
+
+	if _, err := Bundle("api/missing.yaml", load); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Canonicalize returns a copy of the document in a canonical form, so that logically equal documents have the
+// same json representation, which is suitable for hashing. The paths, the required properties and the type
+// arrays are sorted, local references are re-escaped and discriminator mappings refer to full references.
+// Components, which are referenced exactly once, are inlined and removed, so that it does not matter whether
+// a schema is declared in place or as a single-use component. Components of a cycle, components of a
+// discriminator mapping and security schemes are kept and empty components are omitted. The maps are already
+// sorted by the json encoder.
+func (d *Document) Canonicalize() *Document {
+	v, err := (&copier{rewrite: canonicalRef, convert: canonicalSchema}).copy(reflect.ValueOf(d))
+	if err != nil {
+		// cannot happen, because no references are expanded
+		panic(err)
+	}
+	doc := v.Interface().(*Document)
+
+	if inlined := doc.singleUseComponents(); len(inlined) > 0 {
+		c := &copier{doc: doc, inline: func(ref string) bool { return inlined[ref] }}
+		if v, err := c.copy(reflect.ValueOf(doc)); err == nil {
+			doc = v.Interface().(*Document)
+			doc.removeComponents(inlined)
+		}
+	}
+
+	if b, err := json.Marshal(doc.Components); err == nil && string(b) == "{}" {
+		doc.Components = nil
+	}
+
+	keys := doc.Paths.Keys()
+	sort.Strings(keys)
+	entries := make([]PathEntry, 0, len(keys))
+	for _, key := range keys {
+		item, _ := doc.Paths.Get(key)
+		entries = append(entries, PathEntry{Path: key, Item: item})
+	}
+	doc.Paths = NewPaths(entries...)
+
+	return doc
+}
+
+// Hash returns the hex encoded sha256 checksum of the canonical json form, so that logically equal documents
+// have the same hash, e.g. to detect changes of a specification independent of its formatting.
+func (d *Document) Hash() string {
+	sum := sha256.Sum256([]byte(d.Canonicalize().String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalRef returns the local reference with uniformly escaped tokens. Other references are only trimmed.
+func canonicalRef(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if !strings.HasPrefix(ref, "#") {
+		return ref
+	}
+
+	tokens, err := parsePointer(ref)
+	if err != nil {
+		return ref
+	}
+	return pointerOf(append([]string{"#"}, tokens...)...)
+}
+
+// canonicalSchema sorts the required properties and the type array and completes the discriminator mapping.
+func canonicalSchema(s *Schema) {
+	sort.Strings(s.Required)
+	sort.Slice(s.Types, func(i, j int) bool { return s.Types[i] < s.Types[j] })
+
+	if s.Discriminator != nil {
+		for value, ref := range s.Discriminator.Mapping {
+			if !strings.HasPrefix(ref, "#") {
+				// a plain name implies a schema of the components
+				ref = pointerOf("components", "schemas", ref)
+			}
+			s.Discriminator.Mapping[value] = canonicalRef(ref)
+		}
+	}
+}
+
+// singleUseComponents returns the references of the components, which are referenced exactly once by a $ref
+// and which are neither part of a cycle, nor mapped by a discriminator nor a security scheme.
+func (d *Document) singleUseComponents() map[string]bool {
+	if d.Components == nil {
+		return nil
+	}
+
+	counts := map[string]int{}
+	kept := map[string]bool{}
+	_ = d.Walk(func(path []string, node interface{}) error {
+		if ref := refOf(reflect.ValueOf(node)); ref != "" {
+			counts[ref]++
+		}
+
+		if discriminator, ok := node.(Discriminator); ok {
+			for _, ref := range discriminator.Mapping {
+				kept[ref] = true
+			}
+		}
+		return nil
+	})
+
+	for _, cycle := range d.DetectCycles() {
+		for _, name := range cycle {
+			kept[pointerOf("components", "schemas", name)] = true
+		}
+	}
+
+	components := reflect.ValueOf(d.Components).Elem()
+	securitySchemes := pointerOf("components", "securitySchemes") + "/"
+	refs := map[string]bool{}
+	for ref, count := range counts {
+		if count != 1 || kept[ref] || strings.HasPrefix(ref, securitySchemes) {
+			continue
+		}
+
+		if _, ok := componentValue(components, ref); ok {
+			refs[ref] = true
+		}
+	}
+	return refs
+}
+
+// removeComponents deletes the referenced components.
+func (d *Document) removeComponents(refs map[string]bool) {
+	components := reflect.ValueOf(d.Components).Elem()
+	for ref := range refs {
+		tokens, err := parsePointer(ref)
+		if err != nil || len(tokens) != 3 {
+			continue
+		}
+
+		if field, ok := jsonField(components.Type(), tokens[1]); ok && field.Type.Kind() == reflect.Map {
+			components.FieldByIndex(field.Index).SetMapIndex(reflect.ValueOf(tokens[2]), reflect.Value{})
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_canonicalize(t *testing.T) {
+	pet := func() Schema {
+		return Schema{Type: Object, Required: []string{"name", "id"}, Properties: map[string]Schema{
+			"id":   {Type: Integer},
+			"name": {Type: String},
+		}}
+	}
+
+	inline := validDocument()
+	inline.Paths.Set("/pets", PathItem{Get: &Operation{Responses: map[string]Response{
+		"200": {Description: "ok", Content: JSONContent(pet())},
+	}}})
+
+	ref := "#/components/schemas/Pet"
+	referenced := NewDocument()
+	referenced.Info = Info{Title: "Demo API", Version: "0.0.1"}
+	referenced.Components = &Components{Schemas: map[string]Schema{
+		"Pet": {Type: Object, Required: []string{"id", "name"}, Properties: pet().Properties},
+	}}
+	referenced.Paths.Set("/pets", PathItem{Get: &Operation{Responses: map[string]Response{
+		"200": {Description: "ok", Content: JSONContent(Schema{Ref: &ref})},
+	}}})
+	referenced.Paths.Set("/pets/{id}", *validDocument().Paths.Item("/pets/{id}"))
+
+	if a, b := inline.Hash(), referenced.Hash(); a != b {
+		t.Fatalf("expected equal hashes but got\n%s\n%s", inline.Canonicalize().String(), referenced.Canonicalize().String())
+	}
+
+	if _, ok := referenced.Components.Schemas["Pet"]; !ok {
+		t.Fatal("expected the original document to be unchanged")
+	}
+
+	if keys := referenced.Canonicalize().Paths.Keys(); strings.Join(keys, ",") != "/pets,/pets/{id}" {
+		t.Fatalf("expected sorted paths but got %v", keys)
+	}
+
+	changed := inline.Clone()
+	changed.Paths.Item("/pets").Get.Responses["200"].Content["application/json"].Schema.Properties["id"] = Schema{Type: String}
+	if inline.Hash() == changed.Hash() {
+		t.Fatal("expected different hashes for different documents")
+	}
+}
+
+func Test_canonicalizeKeepsSharedComponents(t *testing.T) {
+	pet := "#/components/schemas/Pet"
+	node := "#/components/schemas/Node"
+	doc := validDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Pet":  {Type: Object},
+		"Node": {Type: Object, Properties: map[string]Schema{"children": {Type: Array, Items: &Items{&Schema{Ref: &node}}}}},
+	}}
+	doc.Paths.Set("/pets", PathItem{
+		Get:  &Operation{Responses: map[string]Response{"200": {Description: "ok", Content: JSONContent(Schema{Ref: &pet})}}},
+		Post: &Operation{Responses: map[string]Response{"200": {Description: "ok", Content: JSONContent(Schema{Ref: &pet})}}},
+	})
+	doc.Paths.Set("/tree", PathItem{Get: &Operation{Responses: map[string]Response{
+		"200": {Description: "ok", Content: JSONContent(Schema{Ref: &node})},
+	}}})
+
+	canonical := doc.Canonicalize()
+	if len(canonical.Components.Schemas) != 2 {
+		t.Fatalf("expected the shared and the recursive schema to be kept but got %v", canonical.Components.Schemas)
+	}
+
+	if doc.Hash() != doc.Clone().Hash() {
+		t.Fatal("expected a stable hash")
+	}
+}
+
+func Test_canonicalRef(t *testing.T) {
+	if ref := canonicalRef(" #/components/schemas/a~1b "); ref != "#/components/schemas/a~1b" {
+		t.Fatalf("unexpected ref %s", ref)
+	}
+
+	if ref := canonicalRef("pets.json#/Pet"); ref != "pets.json#/Pet" {
+		t.Fatalf("unexpected ref %s", ref)
+	}
+
+	s := Schema{Discriminator: &Discriminator{PropertyName: "kind", Mapping: map[string]string{"dog": "Dog"}}}
+	canonicalSchema(&s)
+	if s.Discriminator.Mapping["dog"] != "#/components/schemas/Dog" {
+		t.Fatalf("unexpected mapping %v", s.Discriminator.Mapping)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Clone returns a deep copy of the document, which does not share any pointers, maps or slices with the
+// original, so that the copy can be modified safely. Free-form values like examples are copied as well.
+func (d *Document) Clone() *Document {
+	v, err := (&copier{}).copy(reflect.ValueOf(d))
+	if err != nil {
+		// cannot happen, because only the expansion of references may fail
+		panic(err)
+	}
+	return v.Interface().(*Document)
+}
+
+// Dereference returns a deep copy of the document, where every $ref is replaced by a copy of the referenced
+// component. A reference, which refers to a component which is already being expanded (a recursive schema like a
+// tree node), is kept in place. Only local component references can be resolved, anything else is an error.
+func (d *Document) Dereference() (*Document, error) {
+	c := &copier{doc: d}
+	v, err := c.copy(reflect.ValueOf(d))
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface().(*Document), nil
+}
+
+// A copier creates deep copies of model values. If doc is not nil, references are expanded, optionally only
+// those selected by inline. If rewrite is not nil, it replaces each kept reference. If convert is not nil, it is
+// applied to each copied schema.
+type copier struct {
+	doc     *Document
+	inline  func(ref string) bool
+	rewrite func(ref string) string
+	convert func(s *Schema)
+	stack   []string // stack of references which are currently expanded
+}
+
+// copy returns a deep copy of v, which does not share any pointers, maps or slices with v.
+func (c *copier) copy(v reflect.Value) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v, nil
+		}
+		elem, err := c.copy(v.Elem())
+		if err != nil {
+			return v, err
+		}
+		p := reflect.New(elem.Type())
+		p.Elem().Set(elem)
+		return p, nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, nil
+		}
+		elem, err := c.copy(v.Elem())
+		if err != nil {
+			return v, err
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(elem)
+		return out, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return v, nil
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elem, err := c.copy(iter.Value())
+			if err != nil {
+				return v, err
+			}
+			out.SetMapIndex(iter.Key(), elem)
+		}
+		return out, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return v, nil
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem, err := c.copy(v.Index(i))
+			if err != nil {
+				return v, err
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, nil
+	case reflect.Struct:
+		if c.doc != nil {
+			if ref := refOf(v); ref != "" && (c.inline == nil || c.inline(ref)) {
+				return c.expand(v, ref)
+			}
+		}
+
+		if paths, ok := v.Interface().(Paths); ok {
+			return c.copyPaths(paths)
+		}
+
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			field, err := c.copy(v.Field(i))
+			if err != nil {
+				return v, err
+			}
+			out.Field(i).Set(field)
+		}
+
+		if ref := refOf(out); ref != "" && c.rewrite != nil {
+			ref = c.rewrite(ref)
+			out.FieldByName("Ref").Set(reflect.ValueOf(&ref))
+		}
+
+		if schema, ok := out.Addr().Interface().(*Schema); ok && c.convert != nil {
+			c.convert(schema)
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// copyPaths returns a deep copy of the paths in the same order.
+func (c *copier) copyPaths(paths Paths) (reflect.Value, error) {
+	var out Paths
+	for _, entry := range paths.all() {
+		item, err := c.copy(reflect.ValueOf(entry.Item))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Set(entry.Path, item.Interface().(PathItem))
+	}
+	return reflect.ValueOf(out), nil
+}
+
+// expand returns a copy of the referenced component instead of v.
+func (c *copier) expand(v reflect.Value, ref string) (reflect.Value, error) {
+	for _, r := range c.stack {
+		if r == ref {
+			// a cycle, keep the reference as is
+			doc := c.doc
+			c.doc = nil
+			out, err := c.copy(v)
+			c.doc = doc
+			return out, err
+		}
+	}
+
+	_, component, err := c.doc.ResolveComponent(ref)
+	if err != nil {
+		return v, err
+	}
+
+	resolved := reflect.ValueOf(component).Elem()
+	if resolved.Type() != v.Type() {
+		return v, fmt.Errorf("'%s' refers to a %s but expected a %s", ref, resolved.Type().Name(), v.Type().Name())
+	}
+
+	c.stack = append(c.stack, ref)
+	out, err := c.copy(resolved)
+	c.stack = c.stack[:len(c.stack)-1]
+	return out, err
+}
+
+// refOf returns the value of the $ref field of a model struct or the empty string.
+func refOf(v reflect.Value) string {
+	f, ok := v.Type().FieldByName("Ref")
+	if !ok || len(f.Index) != 1 || f.Type != reflect.TypeOf((*string)(nil)) {
+		// not declared directly, e.g. promoted from the embedded schema of Items
+		return ""
+	}
+
+	field := v.Field(f.Index[0])
+	if field.IsNil() {
+		return ""
+	}
+	return *field.Interface().(*string)
+}
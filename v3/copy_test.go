@@ -0,0 +1,241 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_dereference(t *testing.T) {
+	pet := "#/components/schemas/Pet"
+	category := "#/components/schemas/Category"
+	name := "#/components/schemas/Name"
+	node := "#/components/schemas/Node"
+	limit := "#/components/parameters/limit"
+
+	doc := NewDocument()
+	doc.Components = &Components{
+		Schemas: map[string]Schema{
+			"Pet":      {Type: Object, Properties: map[string]Schema{"category": {Ref: &category}}},
+			"Category": {Type: Object, Properties: map[string]Schema{"name": {Ref: &name}}},
+			"Name":     {Type: String},
+			"Node":     {Type: Object, Properties: map[string]Schema{"children": {Type: Array, Items: &Items{&Schema{Ref: &node}}}}},
+		},
+		Parameters: map[string]Parameter{"limit": {Name: "limit", In: QueryLocation}},
+	}
+	doc.Paths.Set("/pets", PathItem{
+		Get: &Operation{
+			Parameters: []Parameter{{Ref: &limit}},
+			Responses: map[string]Response{
+				"200": {Description: "ok", Content: map[string]MediaType{"application/json": {Schema: Schema{Ref: &pet}}}},
+			},
+		},
+	})
+	doc.Paths.Set("/tree", PathItem{
+		Get: &Operation{
+			Responses: map[string]Response{
+				"200": {Description: "ok", Content: map[string]MediaType{"application/json": {Schema: Schema{Ref: &node}}}},
+			},
+		},
+	})
+
+	deref, err := doc.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a simple ref
+	if params := deref.Paths.Item("/pets").Get.Parameters; params[0].Ref != nil || params[0].Name != "limit" {
+		t.Fatalf("expected an inlined parameter but got %+v", params[0])
+	}
+
+	// a nested ref chain
+	schema := deref.Paths.Item("/pets").Get.Responses["200"].Content["application/json"].Schema
+	if schema.Ref != nil || schema.Properties["category"].Properties["name"].Type != String {
+		t.Fatalf("expected an inlined schema but got %+v", schema)
+	}
+
+	// a recursive schema keeps the ref in place
+	schema = deref.Paths.Item("/tree").Get.Responses["200"].Content["application/json"].Schema
+	items := schema.Properties["children"].Items
+	if schema.Ref != nil || items.Ref == nil || *items.Ref != node {
+		t.Fatalf("expected an inlined schema with a recursive ref but got %+v", schema)
+	}
+
+	// the original is untouched
+	if doc.Paths.Item("/pets").Get.Parameters[0].Ref == nil {
+		t.Fatal("expected the original document to be unchanged")
+	}
+
+	dangling := "#/components/schemas/Unknown"
+	doc.Components.Schemas["Dangling"] = Schema{Ref: &dangling}
+	if _, err := doc.Dereference(); err == nil {
+		t.Fatal("expected an error for a dangling ref")
+	}
+}
+
+func Test_clone(t *testing.T) {
+	doc := NewDocument()
+	doc.Path("/pets").Get().Response(200, Response{Description: "ok"}).Tags("pets")
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Pet": {Type: Object, Example: map[string]interface{}{"id": 1}},
+	}}
+
+	clone := doc.Clone()
+	clone.Paths.Item("/pets").Get.Responses["200"] = Response{Description: "changed"}
+	clone.Paths.Item("/pets").Get.Tags[0] = "dogs"
+	clone.Components.Schemas["Pet"].Example.(map[string]interface{})["id"] = 2
+	clone.Paths.Set("/dogs", PathItem{})
+
+	if desc := doc.Paths.Item("/pets").Get.Responses["200"].Description; desc != "ok" {
+		t.Fatalf("expected the original response to be unchanged but got %s", desc)
+	}
+	if tag := doc.Paths.Item("/pets").Get.Tags[0]; tag != "pets" {
+		t.Fatalf("expected the original tags to be unchanged but got %s", tag)
+	}
+	if id := doc.Components.Schemas["Pet"].Example.(map[string]interface{})["id"]; id != 1 {
+		t.Fatalf("expected the original example to be unchanged but got %v", id)
+	}
+	if doc.Paths.Len() != 1 {
+		t.Fatalf("expected the original paths to be unchanged but got %v", doc.Paths)
+	}
+}
+
+func Test_dereferenceHeader(t *testing.T) {
+	rateLimit := "#/components/headers/X-Rate-Limit"
+	headers := map[string]Header{"X-Rate-Limit": {Ref: &rateLimit}}
+
+	doc := NewDocument()
+	doc.Path("/pets").
+		Get().Response(200, Response{Description: "ok", Headers: headers}).
+		Path().
+		Post().Response(201, Response{Description: "created", Headers: headers})
+	doc.Components = &Components{Headers: map[string]Header{
+		"X-Rate-Limit": {Description: "requests per hour", Schema: Schema{Type: Integer}},
+	}}
+
+	b, err := json.Marshal(doc.Paths.Item("/pets").Get.Responses["200"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"description":"ok","headers":{"X-Rate-Limit":{"$ref":"#/components/headers/X-Rate-Limit"}}}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	if name, header := doc.ResolveHeaderRef(rateLimit); name != "X-Rate-Limit" || header.Schema.Type != Integer {
+		t.Fatalf("cannot resolve the header: %s %v", name, header)
+	}
+
+	deref, err := doc.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, resp := range []Response{deref.Paths.Item("/pets").Get.Responses["200"], deref.Paths.Item("/pets").Post.Responses["201"]} {
+		if header := resp.Headers["X-Rate-Limit"]; header.Ref != nil || header.Description != "requests per hour" {
+			t.Fatalf("expected an expanded header but got %+v", header)
+		}
+	}
+}
+
+func Test_dereferenceParameterAndResponse(t *testing.T) {
+	limit, notFound := "#/components/parameters/limit", "#/components/responses/NotFound"
+
+	doc := NewDocument()
+	doc.Path("/pets").
+		Get().
+		Parameter(Parameter{Ref: &limit}).
+		Response(200, Response{Description: "ok"}).
+		Response(404, Response{Ref: &notFound})
+	doc.Components = &Components{
+		Parameters: map[string]Parameter{"limit": {Name: "limit", In: QueryLocation, Schema: Schema{Type: Integer}}},
+		Responses:  map[string]Response{"NotFound": {Description: "not found"}},
+	}
+
+	b, err := json.Marshal(doc.Paths.Item("/pets").Get)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"parameters":[{"$ref":"#/components/parameters/limit"}],"responses":{"200":{"description":"ok"},"404":{"$ref":"#/components/responses/NotFound"}}}`
+	if string(b) != expected {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	parsed, err := FromJson([]byte(doc.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ref := parsed.Paths.Item("/pets").Get.Responses["404"].Ref; ref == nil || *ref != notFound {
+		t.Fatalf("expected the response reference to be parsed but got %v", ref)
+	}
+
+	deref, err := doc.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := deref.Paths.Item("/pets").Get
+	if p := op.Parameters[0]; p.Ref != nil || p.Name != "limit" {
+		t.Fatalf("expected an expanded parameter but got %+v", p)
+	}
+
+	if resp := op.Responses["404"]; resp.Ref != nil || resp.Description != "not found" {
+		t.Fatalf("expected an expanded response but got %+v", resp)
+	}
+}
+
+func Test_dereferenceRequestBody(t *testing.T) {
+	src := `{
+		"openapi": "3.0.3",
+		"info": {"title": "pets", "version": "1"},
+		"paths": {"/pets": {"post": {
+			"requestBody": {"$ref": "#/components/requestBodies/Pet"},
+			"responses": {"201": {"description": "created"}}
+		}}},
+		"components": {"requestBodies": {"Pet": {
+			"required": true,
+			"content": {"application/json": {"schema": {"type": "object"}}}
+		}}}
+	}`
+
+	doc, err := FromJsonStrict([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(doc.Paths.Item("/pets").Post.RequestBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"$ref":"#/components/requestBodies/Pet"}` {
+		t.Fatalf("expected only the reference but got %s", b)
+	}
+
+	deref, err := doc.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body := deref.Paths.Item("/pets").Post.RequestBody; body.Ref != nil || !body.Required || len(body.Content) != 1 {
+		t.Fatalf("expected an expanded request body but got %+v", body)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DetectCycles returns the names of the component schemas, which reference each other in a cycle, e.g. [Node]
+// for a tree node with children of its own type or [A B] for two mutually referencing schemas. Each cycle is a
+// strongly connected component of the reference graph, so every schema of a cycle reaches all the others. The
+// names of a cycle and the cycles are sorted.
+func (d *Document) DetectCycles() [][]string {
+	if d.Components == nil {
+		return nil
+	}
+
+	graph := map[string][]string{}
+	for _, name := range sortedKeys(d.Components.Schemas) {
+		graph[name] = schemaRefs(d.Components.Schemas[name])
+	}
+
+	// Tarjan's algorithm for strongly connected components
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var cycles [][]string
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		selfLoop := false
+		for _, next := range graph[name] {
+			if _, exists := graph[next]; !exists {
+				continue
+			}
+
+			if next == name {
+				selfLoop = true
+			}
+
+			if _, visited := index[next]; !visited {
+				connect(next)
+				if lowLink[next] < lowLink[name] {
+					lowLink[name] = lowLink[next]
+				}
+			} else if onStack[next] && index[next] < lowLink[name] {
+				lowLink[name] = index[next]
+			}
+		}
+
+		if lowLink[name] != index[name] {
+			return
+		}
+
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == name {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfLoop {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, name := range sortedKeys(graph) {
+		if _, visited := index[name]; !visited {
+			connect(name)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return strings.Join(cycles[i], "/") < strings.Join(cycles[j], "/")
+	})
+
+	return cycles
+}
+
+// schemaRefs returns the names of the component schemas, which are referenced by the schema or any of its
+// nested schemas, in sorted order and without duplicates.
+func schemaRefs(schema Schema) []string {
+	seen := map[string]bool{}
+	_ = walkValue(reflect.ValueOf(schema), nil, func(path []string, node interface{}) error {
+		if s, ok := node.(Schema); ok && s.Ref != nil && strings.HasPrefix(*s.Ref, schemasPrefix) {
+			seen[unescapePointerToken((*s.Ref)[len(schemasPrefix):])] = true
+		}
+		return nil
+	})
+	return sortedKeys(seen)
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_detectCycles(t *testing.T) {
+	node := "#/components/schemas/Node"
+	a := "#/components/schemas/A"
+	b := "#/components/schemas/B"
+	leaf := "#/components/schemas/Leaf"
+
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Node": {Type: Object, Properties: map[string]Schema{"children": {Type: Array, Items: &Items{&Schema{Ref: &node}}}}},
+		"A":    {Type: Object, Properties: map[string]Schema{"b": {Ref: &b}, "leaf": {Ref: &leaf}}},
+		"B":    {AllOf: []Schema{{Ref: &a}}},
+		"Leaf": {Type: String},
+		"Root": {Type: Object, Properties: map[string]Schema{"a": {Ref: &a}, "node": {Ref: &node}}},
+	}}
+
+	expected := [][]string{{"A", "B"}, {"Node"}}
+	if cycles := doc.DetectCycles(); !reflect.DeepEqual(cycles, expected) {
+		t.Fatalf("expected %v but got %v", expected, cycles)
+	}
+
+	if cycles := NewDocument().DetectCycles(); len(cycles) != 0 {
+		t.Fatalf("expected no cycles but got %v", cycles)
+	}
+}
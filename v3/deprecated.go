@@ -0,0 +1,67 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+// DeprecationKind classifies a Deprecation.
+type DeprecationKind string
+
+const (
+	DeprecatedOperation DeprecationKind = "operation"
+	DeprecatedParameter DeprecationKind = "parameter"
+	DeprecatedHeader    DeprecationKind = "header"
+	DeprecatedSchema    DeprecationKind = "schema"
+)
+
+// A Deprecation locates a model object, which is marked as deprecated.
+type Deprecation struct {
+	Location string          // Location is the json pointer of the object, e.g. #/paths/~1pets/get
+	Kind     DeprecationKind // Kind is one of operation, parameter, header or schema, which includes schema properties
+}
+
+// DeprecatedItems returns all operations, parameters, headers and schemas, including the properties of schemas,
+// which are marked as deprecated, in the order of Walk. Referenced objects are reported at their declaration
+// within the components.
+func (d *Document) DeprecatedItems() []Deprecation {
+	var r []Deprecation
+	_ = d.Walk(func(path []string, node interface{}) error {
+		var kind DeprecationKind
+		switch t := node.(type) {
+		case Operation:
+			if t.Deprecated {
+				kind = DeprecatedOperation
+			}
+		case Parameter:
+			if t.Deprecated {
+				kind = DeprecatedParameter
+			}
+		case Header:
+			if t.Deprecated {
+				kind = DeprecatedHeader
+			}
+		case Schema:
+			if t.Deprecated {
+				kind = DeprecatedSchema
+			}
+		}
+
+		if kind != "" {
+			r = append(r, Deprecation{Location: pointerOf(path...), Kind: kind})
+		}
+		return nil
+	})
+	return r
+}
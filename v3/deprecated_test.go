@@ -0,0 +1,44 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_deprecatedItems(t *testing.T) {
+	doc := validDocument()
+	doc.Paths.Set("/pets", PathItem{Get: &Operation{
+		Deprecated: true,
+		Parameters: []Parameter{{Name: "limit", In: QueryLocation, Schema: Schema{Type: Integer}, Deprecated: true}},
+		Responses:  map[string]Response{"200": {Description: "ok"}},
+	}})
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Pet": {Type: Object, Properties: map[string]Schema{"tag": {Type: String, Deprecated: true}}},
+	}}
+
+	expected := []Deprecation{
+		{Location: "#/paths/~1pets/get", Kind: DeprecatedOperation},
+		{Location: "#/paths/~1pets/get/parameters/0", Kind: DeprecatedParameter},
+		{Location: "#/components/schemas/Pet/properties/tag", Kind: DeprecatedSchema},
+	}
+
+	if items := doc.DeprecatedItems(); !reflect.DeepEqual(items, expected) {
+		t.Fatalf("expected %v but got %v", expected, items)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind classifies a Change.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// A Change describes a single structural difference between two versions of a document.
+type Change struct {
+	Kind        ChangeKind // Kind tells if something has been added, removed or modified
+	Location    string     // Location is the json pointer of the changed node
+	Description string     // Description is a human readable summary
+	Breaking    bool       // Breaking is true, if existing clients may fail
+}
+
+func (c Change) String() string {
+	if c.Breaking {
+		return "breaking: " + c.Description
+	}
+	return c.Description
+}
+
+// Diff compares the paths, operations, parameters, responses and component schemas of both documents. Removed
+// paths, operations, responses and properties, new required parameters and changed types are breaking changes.
+func Diff(oldDoc, newDoc *Document) []Change {
+	var changes []Change
+	for _, path := range unionKeys(oldDoc.Paths, newDoc.Paths) {
+		oldItem, inOld := oldDoc.Paths.Get(path)
+		newItem, inNew := newDoc.Paths.Get(path)
+		loc := pointerOf("paths", path)
+		switch {
+		case !inOld:
+			changes = append(changes, Change{ChangeAdded, loc, fmt.Sprintf("path %s has been added", path), false})
+		case !inNew:
+			changes = append(changes, Change{ChangeRemoved, loc, fmt.Sprintf("path %s has been removed", path), true})
+		default:
+			changes = append(changes, diffPathItem(path, oldItem, newItem)...)
+		}
+	}
+
+	var oldSchemas, newSchemas map[string]Schema
+	if oldDoc.Components != nil {
+		oldSchemas = oldDoc.Components.Schemas
+	}
+	if newDoc.Components != nil {
+		newSchemas = newDoc.Components.Schemas
+	}
+
+	for _, name := range unionKeys(oldSchemas, newSchemas) {
+		oldSchema, inOld := oldSchemas[name]
+		newSchema, inNew := newSchemas[name]
+		loc := pointerOf("components", "schemas", name)
+		switch {
+		case !inOld:
+			changes = append(changes, Change{ChangeAdded, loc, fmt.Sprintf("schema %s has been added", name), false})
+		case !inNew:
+			changes = append(changes, Change{ChangeRemoved, loc, fmt.Sprintf("schema %s has been removed", name), true})
+		default:
+			changes = append(changes, diffSchema(loc, "schema "+name, oldSchema, newSchema)...)
+		}
+	}
+
+	return changes
+}
+
+func diffPathItem(path string, oldItem, newItem PathItem) []Change {
+	var changes []Change
+	oldOps, newOps := oldItem.Map(), newItem.Map()
+	for _, method := range unionKeys(oldOps, newOps) {
+		oldOp, newOp := oldOps[method], newOps[method]
+		loc := pointerOf("paths", path, strings.ToLower(method))
+		name := method + " " + path
+		switch {
+		case oldOp == nil:
+			changes = append(changes, Change{ChangeAdded, loc, fmt.Sprintf("operation %s has been added", name), false})
+		case newOp == nil:
+			changes = append(changes, Change{ChangeRemoved, loc, fmt.Sprintf("operation %s has been removed", name), true})
+		default:
+			changes = append(changes, diffOperation(loc, name, oldOp.EffectiveParameters(&oldItem), newOp.EffectiveParameters(&newItem), oldOp, newOp)...)
+		}
+	}
+	return changes
+}
+
+func diffOperation(loc, name string, oldParams, newParams []Parameter, oldOp, newOp *Operation) []Change {
+	var changes []Change
+	for _, newParam := range newParams {
+		oldParam, found := findParameter(oldParams, newParam)
+		param := fmt.Sprintf("%s parameter '%s' of %s", newParam.In, newParam.Name, name)
+		switch {
+		case !found:
+			changes = append(changes, Change{ChangeAdded, loc + "/parameters", param + " has been added", newParam.Required})
+		case !oldParam.Required && newParam.Required:
+			changes = append(changes, Change{ChangeModified, loc + "/parameters", param + " is now required", true})
+		case oldParam.Schema.Type != newParam.Schema.Type:
+			changes = append(changes, Change{ChangeModified, loc + "/parameters", fmt.Sprintf("%s changed its type from '%s' to '%s'", param, oldParam.Schema.Type, newParam.Schema.Type), true})
+		}
+	}
+
+	for _, oldParam := range oldParams {
+		if _, found := findParameter(newParams, oldParam); !found {
+			changes = append(changes, Change{ChangeRemoved, loc + "/parameters", fmt.Sprintf("%s parameter '%s' of %s has been removed", oldParam.In, oldParam.Name, name), false})
+		}
+	}
+
+	for _, code := range unionKeys(oldOp.Responses, newOp.Responses) {
+		_, inOld := oldOp.Responses[code]
+		_, inNew := newOp.Responses[code]
+		respLoc := pointerOf(loc, "responses", code)
+		switch {
+		case !inOld:
+			changes = append(changes, Change{ChangeAdded, respLoc, fmt.Sprintf("response %s of %s has been added", code, name), false})
+		case !inNew:
+			changes = append(changes, Change{ChangeRemoved, respLoc, fmt.Sprintf("response %s of %s has been removed", code, name), true})
+		}
+	}
+
+	return changes
+}
+
+func diffSchema(loc, name string, oldSchema, newSchema Schema) []Change {
+	var changes []Change
+	if oldSchema.Type != newSchema.Type {
+		changes = append(changes, Change{ChangeModified, loc, fmt.Sprintf("%s changed its type from '%s' to '%s'", name, oldSchema.Type, newSchema.Type), true})
+	}
+
+	for _, prop := range unionKeys(oldSchema.Properties, newSchema.Properties) {
+		oldProp, inOld := oldSchema.Properties[prop]
+		newProp, inNew := newSchema.Properties[prop]
+		propLoc := pointerOf(loc, "properties", prop)
+		propName := fmt.Sprintf("property '%s' of %s", prop, name)
+		switch {
+		case !inOld:
+			changes = append(changes, Change{ChangeAdded, propLoc, propName + " has been added", false})
+		case !inNew:
+			changes = append(changes, Change{ChangeRemoved, propLoc, propName + " has been removed", true})
+		default:
+			changes = append(changes, diffSchema(propLoc, propName, oldProp, newProp)...)
+		}
+	}
+
+	return changes
+}
+
+// findParameter returns the parameter with the same name and location.
+func findParameter(params []Parameter, p Parameter) (Parameter, bool) {
+	for _, candidate := range params {
+		if candidate.Name == p.Name && candidate.In == p.In {
+			return candidate, true
+		}
+	}
+	return Parameter{}, false
+}
+
+// unionKeys returns the sorted and distinct keys of both maps, which must have string keys.
+func unionKeys(a, b interface{}) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, key := range append(sortedKeys(a), sortedKeys(b)...) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func diffDocument() *Document {
+	doc := NewDocument()
+	doc.Path("/pets").Get().
+		Parameter(Parameter{Name: "limit", In: QueryLocation}).
+		Response(200, Response{Description: "ok"}).
+		Response(404, Response{Description: "not found"})
+	return doc
+}
+
+func Test_diff(t *testing.T) {
+	if changes := Diff(diffDocument(), diffDocument()); len(changes) != 0 {
+		t.Fatalf("expected no changes but got %v", changes)
+	}
+
+	// an added endpoint
+	newDoc := diffDocument()
+	newDoc.Path("/dogs").Get().Response(200, Response{Description: "ok"})
+	expected := []Change{{ChangeAdded, "#/paths/~1dogs", "path /dogs has been added", false}}
+	if changes := Diff(diffDocument(), newDoc); !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %v but got %v", expected, changes)
+	}
+
+	// a removed response code
+	newDoc = diffDocument()
+	delete(newDoc.Paths.Item("/pets").Get.Responses, "404")
+	expected = []Change{{ChangeRemoved, "#/paths/~1pets/get/responses/404", "response 404 of GET /pets has been removed", true}}
+	if changes := Diff(diffDocument(), newDoc); !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %v but got %v", expected, changes)
+	}
+
+	// a newly required parameter
+	newDoc = diffDocument()
+	newDoc.Paths.Item("/pets").Get.Parameters[0].Required = true
+	expected = []Change{{ChangeModified, "#/paths/~1pets/get/parameters", "query parameter 'limit' of GET /pets is now required", true}}
+	if changes := Diff(diffDocument(), newDoc); !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %v but got %v", expected, changes)
+	}
+}
+
+func Test_diffSchemas(t *testing.T) {
+	oldDoc := NewDocument()
+	oldDoc.Components = &Components{Schemas: map[string]Schema{
+		"Pet": {Type: Object, Properties: map[string]Schema{"id": {Type: String}, "name": {Type: String}}},
+	}}
+
+	newDoc := NewDocument()
+	newDoc.Components = &Components{Schemas: map[string]Schema{
+		"Pet": {Type: Object, Properties: map[string]Schema{"id": {Type: Integer}, "tag": {Type: String}}},
+	}}
+
+	changes := Diff(oldDoc, newDoc)
+	var descriptions []string
+	for _, c := range changes {
+		descriptions = append(descriptions, c.String())
+	}
+
+	expected := []string{
+		"breaking: property 'id' of schema Pet changed its type from 'string' to 'integer'",
+		"breaking: property 'name' of schema Pet has been removed",
+		"property 'tag' of schema Pet has been added",
+	}
+	if !reflect.DeepEqual(descriptions, expected) {
+		t.Fatalf("expected %v but got %v", expected, descriptions)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolveDiscriminator returns the name and a copy of the concrete schema, which applies to the payload according
+// to the discriminator of the schema. The value of the discriminator property is looked up in the mapping, whose
+// targets are references or schema names. Otherwise the value is the name of the schema, which must be one of the
+// referenced oneOf or anyOf schemas, if declared, or a component schema. It returns an error, if the schema has no
+// discriminator, the payload has no string value for the property or the value is not mapped.
+func (s *Schema) ResolveDiscriminator(doc *Document, payload map[string]interface{}) (string, *Schema, error) {
+	if s.Discriminator == nil {
+		return "", nil, fmt.Errorf("schema has no discriminator")
+	}
+
+	property := s.Discriminator.PropertyName
+	value, ok := payload[property].(string)
+	if !ok || value == "" {
+		return "", nil, fmt.Errorf("missing discriminator property '%s'", property)
+	}
+
+	if target, ok := s.Discriminator.Mapping[value]; ok {
+		if !strings.Contains(target, "/") {
+			target = schemasPrefix + target
+		}
+		return doc.ResolveSchemaRef(target)
+	}
+
+	ref := schemasPrefix + value
+	if members := append(append([]Schema{}, s.OneOf...), s.AnyOf...); len(members) > 0 {
+		found := false
+		for _, member := range members {
+			if member.Ref != nil && *member.Ref == ref {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return "", nil, fmt.Errorf("discriminator value '%s' is not mapped", value)
+		}
+	}
+
+	name, schema, err := doc.ResolveSchemaRef(ref)
+	if err != nil {
+		return "", nil, fmt.Errorf("discriminator value '%s' is not mapped: %w", value, err)
+	}
+	return name, schema, nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import "testing"
+
+func discriminatorDocument() *Document {
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Cat":    {Type: Object, Description: "cat"},
+		"Dog":    {Type: Object, Description: "dog"},
+		"Lizard": {Type: Object, Description: "lizard"},
+	}}
+	return doc
+}
+
+func Test_resolveDiscriminatorMapping(t *testing.T) {
+	cat, dog := "#/components/schemas/Cat", "#/components/schemas/Dog"
+	pet := Schema{
+		OneOf: []Schema{{Ref: &cat}, {Ref: &dog}},
+		Discriminator: &Discriminator{
+			PropertyName: "petType",
+			Mapping:      map[string]string{"kitty": cat, "doggy": "Dog"},
+		},
+	}
+
+	doc := discriminatorDocument()
+	name, schema, err := pet.ResolveDiscriminator(doc, map[string]interface{}{"petType": "kitty"})
+	if err != nil || name != "Cat" || schema.Description != "cat" {
+		t.Fatalf("expected the cat but got %s %v %v", name, schema, err)
+	}
+
+	if name, _, err := pet.ResolveDiscriminator(doc, map[string]interface{}{"petType": "doggy"}); err != nil || name != "Dog" {
+		t.Fatalf("expected the dog by its name but got %s %v", name, err)
+	}
+
+	if _, _, err := pet.ResolveDiscriminator(doc, map[string]interface{}{"name": "Rex"}); err == nil || err.Error() != "missing discriminator property 'petType'" {
+		t.Fatalf("expected a missing property but got %v", err)
+	}
+}
+
+func Test_resolveDiscriminatorImplicit(t *testing.T) {
+	cat, dog := "#/components/schemas/Cat", "#/components/schemas/Dog"
+	pet := Schema{
+		OneOf:         []Schema{{Ref: &cat}, {Ref: &dog}},
+		Discriminator: &Discriminator{PropertyName: "petType"},
+	}
+
+	doc := discriminatorDocument()
+	name, schema, err := pet.ResolveDiscriminator(doc, map[string]interface{}{"petType": "Dog"})
+	if err != nil || name != "Dog" || schema.Description != "dog" {
+		t.Fatalf("expected the dog but got %s %v %v", name, schema, err)
+	}
+
+	if _, _, err := pet.ResolveDiscriminator(doc, map[string]interface{}{"petType": "Lizard"}); err == nil || err.Error() != "discriminator value 'Lizard' is not mapped" {
+		t.Fatalf("expected an unmapped value but got %v", err)
+	}
+}
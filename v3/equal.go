@@ -0,0 +1,102 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Equal compares the schemas structurally by their json representation. The order of the required properties
+// is ignored, also within nested schemas, while the order of other lists like enum or allOf is significant.
+// References are equal, if they point to the same target, and are not resolved. Values like the examples or the
+// enum are compared by their json value, so that e.g. an int and a float64 of the same value are equal.
+func (s Schema) Equal(other Schema) bool {
+	a, err := schemaTree(s)
+	if err != nil {
+		return false
+	}
+
+	b, err := schemaTree(other)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// schemaTree returns the generic json representation of the schema with sorted required lists.
+func schemaTree(s Schema) (interface{}, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree interface{}
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+
+	sortRequired(tree)
+	return tree, nil
+}
+
+// sortRequired sorts the required lists of the generic schema and all of its subschemas. Only schema positions
+// are visited, so that values like examples are kept as is.
+func sortRequired(tree interface{}) {
+	obj, ok := tree.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if required, ok := obj["required"].([]interface{}); ok {
+		sort.Slice(required, func(i, j int) bool {
+			a, _ := required[i].(string)
+			b, _ := required[j].(string)
+			return a < b
+		})
+	}
+
+	for _, sub := range subschemas(obj) {
+		sortRequired(sub)
+	}
+}
+
+// subschemas returns the nested schemas of the generic schema, i.e. the properties, the items, the additional
+// properties and the members of not and the compositions.
+func subschemas(obj map[string]interface{}) []interface{} {
+	var r []interface{}
+	if props, ok := obj["properties"].(map[string]interface{}); ok {
+		for _, key := range sortedKeys(props) {
+			r = append(r, props[key])
+		}
+	}
+
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		if sub, ok := obj[key]; ok {
+			r = append(r, sub)
+		}
+	}
+
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if members, ok := obj[key].([]interface{}); ok {
+			r = append(r, members...)
+		}
+	}
+	return r
+}
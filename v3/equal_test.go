@@ -0,0 +1,58 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import "testing"
+
+func Test_schemaEqual(t *testing.T) {
+	pet := "#/components/schemas/Pet"
+	a := Schema{
+		Type:     Object,
+		Required: []string{"name", "age"},
+		Properties: map[string]Schema{
+			"name":  {Type: String, Enum: []interface{}{"Rex", "Bello"}},
+			"age":   {Type: Integer, Minimum: float64Ptr(0)},
+			"owner": {Type: Object, Required: []string{"id", "email"}, Properties: map[string]Schema{"id": {}, "email": {}}},
+			"pets":  {Type: Array, Items: &Items{&Schema{Ref: &pet}}},
+		},
+	}
+
+	otherPet := "#/components/schemas/Pet"
+	b := Schema{
+		Type:     Object,
+		Required: []string{"age", "name"},
+		Properties: map[string]Schema{
+			"pets":  {Type: Array, Items: &Items{&Schema{Ref: &otherPet}}},
+			"owner": {Type: Object, Required: []string{"email", "id"}, Properties: map[string]Schema{"email": {}, "id": {}}},
+			"age":   {Type: Integer, Minimum: float64Ptr(0)},
+			"name":  {Type: String, Enum: []interface{}{"Rex", "Bello"}},
+		},
+	}
+
+	if !a.Equal(b) {
+		t.Fatal("expected reordered schemas to be equal")
+	}
+
+	b.Properties["name"] = Schema{Type: String, Enum: []interface{}{"Bello", "Rex"}}
+	if a.Equal(b) {
+		t.Fatal("expected the enum order to be significant")
+	}
+
+	if (Schema{Type: String}).Equal(Schema{Type: Integer}) {
+		t.Fatal("expected schemas of different types to differ")
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+// maxExampleDepth limits the nesting of generated examples, to stop at recursive schemas.
+const maxExampleDepth = 16
+
+// GenerateExample synthesizes a sample value for the schema. A declared example, const, default or the first enum
+// value is used as is. Otherwise the value is derived from the type and format, where objects contain all
+// properties and arrays contain a single item. References are resolved against the given document and
+// recursive schemas are cut off at a fixed depth, where optional properties are omitted and required ones
+// become null.
+func (s *Schema) GenerateExample(doc *Document) (interface{}, error) {
+	return s.generateExample(doc, 0)
+}
+
+func (s *Schema) generateExample(doc *Document, depth int) (interface{}, error) {
+	if depth > maxExampleDepth {
+		return nil, nil
+	}
+
+	if s.Ref != nil {
+		_, resolved, err := doc.ResolveSchemaRef(*s.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return resolved.generateExample(doc, depth+1)
+	}
+
+	switch {
+	case s.Example != nil:
+		return s.Example, nil
+	case s.Const != nil:
+		return s.Const, nil
+	case s.Default != nil:
+		return s.Default, nil
+	case len(s.Enum) > 0:
+		return s.Enum[0], nil
+	case len(s.AllOf) > 0:
+		merged := map[string]interface{}{}
+		for _, member := range s.AllOf {
+			v, err := member.generateExample(doc, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if obj, ok := v.(map[string]interface{}); ok {
+				for key, value := range obj {
+					merged[key] = value
+				}
+			}
+		}
+		return merged, nil
+	case len(s.OneOf) > 0:
+		return s.OneOf[0].generateExample(doc, depth+1)
+	case len(s.AnyOf) > 0:
+		return s.AnyOf[0].generateExample(doc, depth+1)
+	}
+
+	switch s.Type {
+	case String:
+		return exampleString(Format(s.Format)), nil
+	case Integer:
+		return 0, nil
+	case Number:
+		return 0.0, nil
+	case Boolean:
+		return false, nil
+	case Array:
+		if s.Items == nil || s.Items.Schema == nil {
+			return []interface{}{}, nil
+		}
+		item, err := s.Items.generateExample(doc, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if item == nil {
+			return []interface{}{}, nil
+		}
+		return []interface{}{item}, nil
+	default:
+		if s.Type == "" && len(s.Properties) == 0 {
+			return nil, nil
+		}
+
+		obj := map[string]interface{}{}
+		for name, prop := range s.Properties {
+			v, err := prop.generateExample(doc, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil || s.IsRequired(name) {
+				obj[name] = v
+			}
+		}
+		return obj, nil
+	}
+}
+
+// exampleString returns a sample value for the string format.
+func exampleString(format Format) string {
+	switch format {
+	case Date:
+		return "1970-01-01"
+	case DateTime:
+		return "1970-01-01T00:00:00Z"
+	case Byte:
+		return "AA=="
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri":
+		return "https://example.com"
+	default:
+		return "string"
+	}
+}
+
+// containsString returns true, if the list contains the string.
+func containsString(list []string, str string) bool {
+	for _, candidate := range list {
+		if candidate == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_generateExample(t *testing.T) {
+	tag := "#/components/schemas/Tag"
+	node := "#/components/schemas/Node"
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Tag":  {Type: Object, Properties: map[string]Schema{"label": {Type: String, Enum: []interface{}{"red", "green"}}}},
+		"Node": {Type: Object, Properties: map[string]Schema{"next": {Ref: &node}}},
+	}}
+
+	schema := Schema{
+		Type: Object,
+		Properties: map[string]Schema{
+			"id":       {Type: Integer, Format: string(Int64)},
+			"created":  {Type: String, Format: string(DateTime)},
+			"active":   {Type: Boolean, Default: true},
+			"tags":     {Type: Array, Items: &Items{&Schema{Ref: &tag}}},
+			"children": {Type: Array, Items: &Items{&Schema{Type: Number}}},
+		},
+	}
+
+	example, err := schema.GenerateExample(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"id":       0,
+		"created":  "1970-01-01T00:00:00Z",
+		"active":   true,
+		"tags":     []interface{}{map[string]interface{}{"label": "red"}},
+		"children": []interface{}{0.0},
+	}
+	if !reflect.DeepEqual(example, expected) {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, example)
+	}
+
+	// a recursive schema must terminate
+	if _, err := (&Schema{Ref: &node}).GenerateExample(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	dangling := "#/components/schemas/Unknown"
+	if _, err := (&Schema{Ref: &dangling}).GenerateExample(doc); err == nil {
+		t.Fatal("expected an error for a dangling ref")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Extensions contains the specification extensions of an object, whose keys must start with x-, e.g.
+// x-rate-limit. The values are kept as raw json, so that they survive a round-trip.
+type Extensions map[string]json.RawMessage
+
+// appendExtensions adds the extensions in sorted order to the end of the marshalled json object.
+func appendExtensions(obj []byte, ext Extensions) ([]byte, error) {
+	if len(ext) == 0 {
+		return obj, nil
+	}
+
+	keys := make([]string, 0, len(ext))
+	for key := range ext {
+		if !strings.HasPrefix(key, "x-") {
+			return nil, fmt.Errorf("extension '%s' must start with x-", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := &bytes.Buffer{}
+	buf.Write(obj[:len(obj)-1])
+	for i, key := range keys {
+		if i > 0 || len(obj) > 2 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		if err := json.Compact(buf, ext[key]); err != nil {
+			return nil, fmt.Errorf("extension '%s': %w", key, err)
+		}
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// parseExtensions returns the x- fields of the json object, which are not declared as fields of the model type.
+func parseExtensions(obj []byte, model reflect.Type) (Extensions, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(obj, &fields); err != nil {
+		return nil, err
+	}
+
+	var ext Extensions
+	for key, value := range fields {
+		if _, declared := jsonField(model, key); !strings.HasPrefix(key, "x-") || declared {
+			continue
+		}
+		if ext == nil {
+			ext = Extensions{}
+		}
+		ext[key] = value
+	}
+	return ext, nil
+}
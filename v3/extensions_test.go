@@ -0,0 +1,81 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_extensions(t *testing.T) {
+	src := `{"openapi":"3.0.3","info":{"title":"api","version":"1.0"},"paths":{"/pets":{"get":{"responses":{"200":{"description":"ok"}},"x-rate-limit":{"limit":100,"window":"1m"}}}},"x-owner":"team"}`
+
+	doc, err := FromJson([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := doc.Paths.Item("/pets").Get
+	if string(op.Extensions["x-rate-limit"]) != `{"limit":100,"window":"1m"}` {
+		t.Fatalf("unexpected operation extensions: %v", op.Extensions)
+	}
+
+	if string(doc.Extensions["x-owner"]) != `"team"` {
+		t.Fatalf("unexpected document extensions: %v", doc.Extensions)
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), `"x-rate-limit":{"limit":100,"window":"1m"}`) || !strings.Contains(string(b), `"x-owner":"team"`) {
+		t.Fatalf("extensions not re-emitted: %s", b)
+	}
+}
+
+func Test_extensionsSchema(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{"type":["string","null"],"x-ee.type":"uuid","x-go-name":"ID"}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if schema.XType == nil || *schema.XType != "uuid" {
+		t.Fatalf("expected x-ee.type in XType")
+	}
+
+	if len(schema.Extensions) != 1 || string(schema.Extensions["x-go-name"]) != `"ID"` {
+		t.Fatalf("unexpected extensions: %v", schema.Extensions)
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"type":["string","null"],"x-ee.type":"uuid","x-go-name":"ID"}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_extensionsInvalidKey(t *testing.T) {
+	param := Parameter{Name: "id", In: "query", Extensions: Extensions{"rate-limit": json.RawMessage(`1`)}}
+	if _, err := json.Marshal(param); err == nil {
+		t.Fatal("expected error for extension without x- prefix")
+	}
+}
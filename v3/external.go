@@ -0,0 +1,157 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An ExternalResolver resolves references into other files, like ./schemas/Pet.json#/Pet or
+// common.yaml#/components/schemas/Error. The files may be in the JSON or YAML format and are loaded only once.
+type ExternalResolver struct {
+	load  func(path string) ([]byte, error)
+	cache map[string]interface{}
+}
+
+// NewExternalResolver creates a resolver, which uses the given function to read the referenced files.
+func NewExternalResolver(load func(path string) ([]byte, error)) *ExternalResolver {
+	return &ExternalResolver{load: load, cache: map[string]interface{}{}}
+}
+
+// DirLoader returns a loader function, which reads the referenced files relative to the given directory.
+func DirLoader(dir string) func(path string) ([]byte, error) {
+	return func(path string) ([]byte, error) {
+		return ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	}
+}
+
+// Resolve loads the referenced file and decodes the node, which is addressed by the fragment, into v.
+func (r *ExternalResolver) Resolve(ref string, v interface{}) error {
+	file, fragment := splitRef(ref)
+	if file == "" {
+		return fmt.Errorf("'%s' is not an external reference", ref)
+	}
+
+	root, err := r.file(file)
+	if err != nil {
+		return err
+	}
+
+	node, err := walkPointer(root, fragment)
+	if err != nil {
+		return fmt.Errorf("cannot resolve '%s': %w", ref, err)
+	}
+
+	b, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// ResolveSchema resolves the referenced schema from an external file.
+func (r *ExternalResolver) ResolveSchema(ref string) (*Schema, error) {
+	schema := &Schema{}
+	if err := r.Resolve(ref, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// file returns the cached generic tree of the file or loads it.
+func (r *ExternalResolver) file(name string) (interface{}, error) {
+	if tree, ok := r.cache[name]; ok {
+		return tree, nil
+	}
+
+	buf, err := r.load(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load '%s': %w", name, err)
+	}
+
+	// YAML is a superset of JSON, so both formats are parsed the same way
+	var tree interface{}
+	if err := yaml.Unmarshal(buf, &tree); err != nil {
+		return nil, fmt.Errorf("cannot parse '%s': %w", name, err)
+	}
+
+	tree = jsonValue(tree)
+	r.cache[name] = tree
+	return tree, nil
+}
+
+// splitRef separates the cleaned file path of a reference from its fragment.
+func splitRef(ref string) (file, fragment string) {
+	if i := strings.Index(ref, "#"); i >= 0 {
+		file, fragment = ref[:i], ref[i+1:]
+	} else {
+		file = ref
+	}
+
+	if file != "" {
+		file = path.Clean(file)
+	}
+	return file, fragment
+}
+
+// walkPointer returns the node of the generic json tree, which is addressed by the (fragment) JSON pointer.
+func walkPointer(root interface{}, pointer string) (interface{}, error) {
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+
+	if pointer == "" {
+		return root, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid json pointer '%s'", pointer)
+	}
+
+	node := root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapePointerToken(token)
+		switch t := node.(type) {
+		case map[string]interface{}:
+			child, ok := t[token]
+			if !ok {
+				return nil, fmt.Errorf("no such key '%s'", token)
+			}
+			node = child
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(t) {
+				return nil, fmt.Errorf("invalid array index '%s'", token)
+			}
+			node = t[idx]
+		default:
+			return nil, fmt.Errorf("cannot resolve '%s' in a scalar", token)
+		}
+	}
+
+	return node, nil
+}
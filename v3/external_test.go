@@ -0,0 +1,66 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func Test_externalResolver(t *testing.T) {
+	dir := filepath.Join("testdata", "external")
+	buf, err := ioutil.ReadFile(filepath.Join(dir, "openapi.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := FromJson(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loads := 0
+	load := DirLoader(dir)
+	resolver := NewExternalResolver(func(path string) ([]byte, error) {
+		loads++
+		return load(path)
+	})
+
+	ref := *doc.Paths.Item("/pets").Get.Responses["200"].Content["application/json"].Schema.Ref
+	for i := 0; i < 2; i++ {
+		schema, err := resolver.ResolveSchema(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if schema.Type != Object || schema.Properties["name"].Type != String || len(schema.Required) != 1 {
+			t.Fatalf("unexpected schema: %+v", schema)
+		}
+	}
+
+	if loads != 1 {
+		t.Fatalf("expected the file to be loaded once but got %d", loads)
+	}
+
+	if _, err := resolver.ResolveSchema("./pet.json#/Dog"); err == nil {
+		t.Fatal("expected an error for a missing fragment")
+	}
+
+	if _, err := resolver.ResolveSchema("./dog.json#/Dog"); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
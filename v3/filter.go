@@ -0,0 +1,73 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+// FilterByTags returns a copy of the document, which only contains the operations tagged with any of the
+// given tags. Path items without remaining operations, tag declarations which are not requested and all
+// components, which are not transitively referenced by the remaining paths, are removed.
+func (d *Document) FilterByTags(tags ...string) *Document {
+	r := d.Clone()
+	for _, path := range r.Paths.Keys() {
+		item := r.Paths.Item(path)
+		for method, op := range item.Map() {
+			if !hasAnyTag(op.Tags, tags) {
+				item.removeOperation(method)
+			}
+		}
+
+		if len(item.Map()) == 0 {
+			r.Paths.Delete(path)
+		}
+	}
+
+	var declared []Tag
+	for _, tag := range r.Tags {
+		if containsString(tags, tag.Name) {
+			declared = append(declared, tag)
+		}
+	}
+	r.Tags = declared
+
+	r.PruneUnusedComponents()
+	return r
+}
+
+// hasAnyTag returns true, if at least one of the tags is in the wanted list.
+func hasAnyTag(tags []string, wanted []string) bool {
+	for _, tag := range tags {
+		if containsString(wanted, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeOperation removes the operation of the upper case http method.
+func (p *PathItem) removeOperation(method string) {
+	switch method {
+	case "GET":
+		p.Get = nil
+	case "POST":
+		p.Post = nil
+	case "DELETE":
+		p.Delete = nil
+	case "PUT":
+		p.Put = nil
+	case "PATCH":
+		p.Patch = nil
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_filterByTags(t *testing.T) {
+	pet := "#/components/schemas/Pet"
+	category := "#/components/schemas/Category"
+	order := "#/components/schemas/Order"
+	user := "#/components/schemas/User"
+
+	doc := NewDocument()
+	doc.Tags = []Tag{{Name: "pets"}, {Name: "store"}, {Name: "users"}}
+	doc.Path("/pets").Get().Tags("pets").Response(200, Response{Description: "ok", Content: JSONContent(Schema{Ref: &pet})})
+	doc.Path("/store/orders").Post().Tags("store").RequestBody(RequestBody{Content: JSONContent(Schema{Ref: &order})}).
+		Response(201, Response{Description: "created"})
+	doc.Path("/users").Get().Tags("users").Response(200, Response{Description: "ok", Content: JSONContent(Schema{Ref: &user})})
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Pet":      {Type: Object, Properties: map[string]Schema{"category": {Ref: &category}}},
+		"Category": {Type: Object},
+		"Order":    {Type: Object, Properties: map[string]Schema{"pet": {Ref: &pet}}},
+		"User":     {Type: Object},
+		"Orphan":   {Type: Object},
+	}}
+
+	filtered := doc.FilterByTags("pets")
+
+	if !reflect.DeepEqual(sortedKeys(filtered.Paths), []string{"/pets"}) {
+		t.Fatalf("unexpected paths %v", sortedKeys(filtered.Paths))
+	}
+
+	if !reflect.DeepEqual(sortedKeys(filtered.Components.Schemas), []string{"Category", "Pet"}) {
+		t.Fatalf("unexpected schemas %v", sortedKeys(filtered.Components.Schemas))
+	}
+
+	if len(filtered.Tags) != 1 || filtered.Tags[0].Name != "pets" {
+		t.Fatalf("unexpected tags %v", filtered.Tags)
+	}
+
+	// the original must be untouched
+	if doc.Paths.Len() != 3 || len(doc.Components.Schemas) != 5 {
+		t.Fatal("the original document has been modified")
+	}
+
+	store := doc.FilterByTags("store")
+	if !reflect.DeepEqual(sortedKeys(store.Components.Schemas), []string{"Category", "Order", "Pet"}) {
+		t.Fatalf("unexpected transitive schemas %v", sortedKeys(store.Components.Schemas))
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import "fmt"
+
+// FlattenAllOf returns a new schema, which merges the allOf members of the schema into a single object schema.
+// References are resolved against the document and nested allOf members are flattened recursively. The properties
+// and required lists of all members are combined, where the first declaration of a property wins. It returns
+// an error, if a reference cannot be resolved, refers to itself or if the members declare different types for
+// the same property or the schema itself.
+func (s *Schema) FlattenAllOf(doc *Document) (*Schema, error) {
+	return s.flattenAllOf(doc, nil)
+}
+
+func (s *Schema) flattenAllOf(doc *Document, refs []string) (*Schema, error) {
+	if s.Ref != nil {
+		for _, ref := range refs {
+			if ref == *s.Ref {
+				return nil, fmt.Errorf("allOf of '%s' refers to itself", ref)
+			}
+		}
+
+		_, resolved, err := doc.ResolveSchemaRef(*s.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return resolved.flattenAllOf(doc, append(refs, *s.Ref))
+	}
+
+	flat := *s
+	flat.AllOf = nil
+	flat.Required = append([]string(nil), s.Required...)
+	flat.Properties = nil
+	for name, prop := range s.Properties {
+		flat.setProperty(name, prop)
+	}
+
+	for i := range s.AllOf {
+		member, err := s.AllOf[i].flattenAllOf(doc, refs)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := flat.mergeMember(doc, member); err != nil {
+			return nil, fmt.Errorf("allOf/%d: %w", i, err)
+		}
+	}
+
+	if flat.Type == "" && len(flat.Properties) > 0 {
+		flat.Type = Object
+	}
+	return &flat, nil
+}
+
+// mergeMember adds the type, properties and required lists of the flattened member.
+func (s *Schema) mergeMember(doc *Document, member *Schema) error {
+	if member.Type != "" && s.Type != "" && member.Type != s.Type {
+		return fmt.Errorf("conflicting types %s and %s", s.Type, member.Type)
+	}
+
+	if s.Type == "" {
+		s.Type = member.Type
+	}
+
+	if s.Description == "" {
+		s.Description = member.Description
+	}
+
+	for _, name := range sortedKeys(member.Properties) {
+		prop := member.Properties[name]
+		existing, ok := s.Properties[name]
+		if !ok {
+			s.setProperty(name, prop)
+			continue
+		}
+
+		a, b := resolvedType(doc, existing), resolvedType(doc, prop)
+		if a != "" && b != "" && a != b {
+			return fmt.Errorf("conflicting types %s and %s of property '%s'", a, b, name)
+		}
+	}
+
+	for _, name := range member.Required {
+		if !containsString(s.Required, name) {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return nil
+}
+
+// setProperty adds the property to a new properties map, so that the maps of the members are not modified.
+func (s *Schema) setProperty(name string, prop Schema) {
+	if s.Properties == nil {
+		s.Properties = map[string]Schema{}
+	}
+	s.Properties[name] = prop
+}
+
+// resolvedType returns the type of the schema or of the referenced schema.
+func resolvedType(doc *Document, s Schema) Type {
+	if s.Ref != nil {
+		if _, resolved := doc.ResolveRef(*s.Ref); resolved != nil {
+			return resolved.Type
+		}
+	}
+	return s.Type
+}
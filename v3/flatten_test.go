@@ -0,0 +1,76 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_flattenAllOf(t *testing.T) {
+	base := "#/components/schemas/Base"
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Base": {
+			Type:       Object,
+			Required:   []string{"id"},
+			Properties: map[string]Schema{"id": {Type: Integer}},
+			AllOf:      []Schema{{Properties: map[string]Schema{"created": {Type: String, Format: string(DateTime)}}}},
+		},
+	}}
+
+	pet := Schema{AllOf: []Schema{
+		{Ref: &base},
+		{Type: Object, Required: []string{"name"}, Properties: map[string]Schema{"name": {Type: String}}},
+	}}
+
+	flat, err := pet.FlattenAllOf(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Schema{
+		Type:     Object,
+		Required: []string{"id", "name"},
+		Properties: map[string]Schema{
+			"id":      {Type: Integer},
+			"created": {Type: String, Format: string(DateTime)},
+			"name":    {Type: String},
+		},
+	}
+
+	if !reflect.DeepEqual(flat, expected) {
+		t.Fatalf("expected\n%s\nbut got\n%s", toJson(t, expected), toJson(t, flat))
+	}
+
+	if len(doc.Components.Schemas["Base"].Properties) != 1 {
+		t.Fatal("expected the members not to be modified")
+	}
+}
+
+func Test_flattenAllOfConflict(t *testing.T) {
+	schema := Schema{AllOf: []Schema{
+		{Type: Object, Properties: map[string]Schema{"id": {Type: Integer}}},
+		{Type: Object, Properties: map[string]Schema{"id": {Type: String}}},
+	}}
+
+	_, err := schema.FlattenAllOf(NewDocument())
+	if err == nil || !strings.Contains(err.Error(), "allOf/1: conflicting types integer and string of property 'id'") {
+		t.Fatalf("expected a conflict but got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaFromValue infers the schema of a go value, e.g. to document runtime data. Integers map to integer with
+// the format int32 or int64, floats to number with float or double, time.Time to a date-time string and []byte to
+// a base64 string. Slices and arrays become arrays, maps with string keys objects with additionalProperties and
+// structs objects with the properties of their json tags, where fields tagged with json:"-" are skipped and those
+// without omitempty are required. Embedded structs contribute their fields. Pointers become nullable. Values in
+// interfaces, like in a map[string]interface{} parsed from json, are inspected, so that each key becomes a
+// property and the first element of a []interface{} determines the items.
+func SchemaFromValue(v interface{}) Schema {
+	return (&inferrer{}).value(reflect.ValueOf(v))
+}
+
+// SchemaForType returns the schema of the go type like SchemaFromValue but each named struct type, which is
+// used by the type, is returned as a separate schema and referenced by #/components/schemas/<name>, so that the
+// schemas can be registered into the components. A recursive type, like a tree node, refers to itself and is then
+// returned as a named schema as well. Types with the same name from different packages are qualified by their
+// package name.
+func SchemaForType(t reflect.Type) (Schema, map[string]Schema) {
+	i := &inferrer{schemas: map[string]Schema{}, names: map[reflect.Type]string{}, used: map[string]bool{}}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t.Name() == "" || t == timeType {
+		return i.value(reflect.Zero(t)), i.schemas
+	}
+
+	name := i.register(t)
+	schema := i.object(reflect.Zero(t))
+	if i.used[name] {
+		i.schemas[name] = schema
+	}
+	return schema, i.schemas
+}
+
+// An inferrer creates schemas from go values or types and tracks the struct types in progress, to stop at
+// recursive types. If schemas is not nil, named struct types are collected there and referenced instead.
+type inferrer struct {
+	stack   []reflect.Type
+	schemas map[string]Schema       // schemas are the named struct types by their name
+	names   map[reflect.Type]string // names are the registered names of the named struct types
+	used    map[string]bool         // used contains the names, which have been referenced
+}
+
+// ref returns a reference to the named struct type and collects its schema on first use.
+func (i *inferrer) ref(t reflect.Type) Schema {
+	name, known := i.names[t]
+	if !known {
+		name = i.register(t)
+		i.schemas[name] = i.object(reflect.Zero(t))
+	}
+
+	i.used[name] = true
+	ref := schemasPrefix + name
+	return Schema{Ref: &ref}
+}
+
+// register reserves a unique name for the type, which is qualified by its package on collisions.
+func (i *inferrer) register(t reflect.Type) string {
+	name := t.Name()
+	for _, other := range i.names {
+		if other == name {
+			name = invalidComponentChars.ReplaceAllString(t.PkgPath(), "_") + "_" + t.Name()
+			break
+		}
+	}
+
+	i.names[t] = name
+	return name
+}
+
+// value returns the schema of v, which may be invalid for a nil interface.
+func (i *inferrer) value(v reflect.Value) Schema {
+	if !v.IsValid() {
+		return Schema{}
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return Schema{}
+		}
+		return i.value(v.Elem())
+	case reflect.Ptr:
+		var schema Schema
+		if v.IsNil() {
+			schema = i.value(reflect.Zero(v.Type().Elem()))
+		} else {
+			schema = i.value(v.Elem())
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return Schema{Type: String, Format: string(Byte)}
+		}
+
+		elem := reflect.Zero(v.Type().Elem())
+		if v.Len() > 0 {
+			elem = v.Index(0)
+		}
+		items := i.value(elem)
+		return Schema{Type: Array, Items: &Items{&items}}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return Schema{Type: Object}
+		}
+
+		if v.Type().Elem().Kind() == reflect.Interface {
+			schema := Schema{Type: Object, Properties: map[string]Schema{}}
+			for _, key := range v.MapKeys() {
+				schema.Properties[key.String()] = i.value(v.MapIndex(key))
+			}
+			return schema
+		}
+
+		elem := i.value(reflect.Zero(v.Type().Elem()))
+		return Schema{Type: Object, AdditionalProperties: &AdditionalProperties{Schema: &elem}}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return Schema{Type: String, Format: string(DateTime)}
+		}
+
+		if i.schemas != nil && v.Type().Name() != "" {
+			return i.ref(v.Type())
+		}
+		return i.object(v)
+	default:
+		return scalarSchema(v.Kind())
+	}
+}
+
+// object returns the object schema of the struct value.
+func (i *inferrer) object(v reflect.Value) Schema {
+	for _, t := range i.stack {
+		if t == v.Type() {
+			// a recursive type, which cannot be inlined
+			return Schema{Type: Object}
+		}
+	}
+
+	i.stack = append(i.stack, v.Type())
+	defer func() { i.stack = i.stack[:len(i.stack)-1] }()
+
+	schema := Schema{Type: Object, Properties: map[string]Schema{}}
+	i.fields(v, &schema)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// fields adds the json fields of the struct value to the object schema, including those of embedded structs.
+func (i *inferrer) fields(v reflect.Value, schema *Schema) {
+	for n := 0; n < v.NumField(); n++ {
+		f := v.Type().Field(n)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			embedded := v.Field(n)
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					embedded = reflect.Zero(embedded.Type().Elem())
+				} else {
+					embedded = embedded.Elem()
+				}
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				i.fields(embedded, schema)
+				continue
+			}
+		}
+
+		name, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+
+		schema.Properties[name] = i.value(v.Field(n))
+		if !strings.Contains(tag, ",omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// scalarSchema returns the schema of the primitive go kind.
+func scalarSchema(kind reflect.Kind) Schema {
+	switch kind {
+	case reflect.Bool:
+		return Schema{Type: Boolean}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return Schema{Type: Integer, Format: string(Int32)}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return Schema{Type: Integer, Format: string(Int64)}
+	case reflect.Float32:
+		return Schema{Type: Number, Format: string(Float)}
+	case reflect.Float64:
+		return Schema{Type: Number, Format: string(Double)}
+	case reflect.String:
+		return Schema{Type: String}
+	default:
+		return Schema{}
+	}
+}
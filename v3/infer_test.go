@@ -0,0 +1,151 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type inferAddress struct {
+	Street string `json:"street"`
+	Zip    *int32 `json:"zip,omitempty"`
+}
+
+type inferAudit struct {
+	Created time.Time `json:"created"`
+}
+
+type inferPerson struct {
+	inferAudit
+	Name     string         `json:"name"`
+	Age      int64          `json:"age,omitempty"`
+	Score    float64        `json:"score"`
+	Tags     []string       `json:"tags,omitempty"`
+	Address  inferAddress   `json:"address"`
+	Labels   map[string]int `json:"labels,omitempty"`
+	Password string         `json:"-"`
+	internal bool
+}
+
+func Test_schemaFromValue(t *testing.T) {
+	schema := SchemaFromValue(inferPerson{Name: "Rex"})
+
+	expected := Schema{
+		Type: Object,
+		Properties: map[string]Schema{
+			"created": {Type: String, Format: "date-time"},
+			"name":    {Type: String},
+			"age":     {Type: Integer, Format: "int64"},
+			"score":   {Type: Number, Format: "double"},
+			"tags":    {Type: Array, Items: &Items{&Schema{Type: String}}},
+			"address": {
+				Type: Object,
+				Properties: map[string]Schema{
+					"street": {Type: String},
+					"zip":    {Type: Integer, Format: "int32", Nullable: true},
+				},
+				Required: []string{"street"},
+			},
+			"labels": {Type: Object, AdditionalProperties: &AdditionalProperties{Schema: &Schema{Type: Integer, Format: "int64"}}},
+		},
+		Required: []string{"address", "created", "name", "score"},
+	}
+
+	if !reflect.DeepEqual(schema, expected) {
+		t.Fatalf("expected\n%s\nbut got\n%s", toJson(t, expected), toJson(t, schema))
+	}
+}
+
+func Test_schemaFromGenericValue(t *testing.T) {
+	value := map[string]interface{}{"ids": []interface{}{1.0, 2.0}, "name": "Rex", "birth": time.Now()}
+	schema := SchemaFromValue(value)
+
+	expected := Schema{
+		Type: Object,
+		Properties: map[string]Schema{
+			"ids":   {Type: Array, Items: &Items{&Schema{Type: Number, Format: "double"}}},
+			"name":  {Type: String},
+			"birth": {Type: String, Format: "date-time"},
+		},
+	}
+
+	if !reflect.DeepEqual(schema, expected) {
+		t.Fatalf("expected\n%s\nbut got\n%s", toJson(t, expected), toJson(t, schema))
+	}
+
+	if schema := SchemaFromValue([]byte("abc")); schema.Type != String || schema.Format != "byte" {
+		t.Fatalf("unexpected schema for bytes %+v", schema)
+	}
+}
+
+type inferTree struct {
+	Value    string       `json:"value"`
+	Parent   *inferTree   `json:"parent,omitempty"`
+	Children []*inferTree `json:"children,omitempty"`
+}
+
+func Test_schemaForType(t *testing.T) {
+	schema, schemas := SchemaForType(reflect.TypeOf(&inferPerson{}))
+
+	address := "#/components/schemas/inferAddress"
+	if prop := schema.Properties["address"]; prop.Ref == nil || *prop.Ref != address {
+		t.Fatalf("expected a reference to the address but got %s", toJson(t, prop))
+	}
+
+	if _, ok := schema.Properties["created"]; !ok {
+		t.Fatalf("expected the fields of the embedded type to be flattened but got %s", toJson(t, schema))
+	}
+
+	if len(schemas) != 1 || !reflect.DeepEqual(schemas["inferAddress"], SchemaFromValue(inferAddress{})) {
+		t.Fatalf("expected only the address schema but got %s", toJson(t, schemas))
+	}
+}
+
+func Test_schemaForRecursiveType(t *testing.T) {
+	schema, schemas := SchemaForType(reflect.TypeOf(inferTree{}))
+
+	tree := "#/components/schemas/inferTree"
+	if ref := schema.Properties["parent"].Ref; ref == nil || *ref != tree {
+		t.Fatalf("expected a reference to the tree but got %s", toJson(t, schema))
+	}
+
+	if ref := schema.Properties["children"].Items.Schema.Ref; ref == nil || *ref != tree {
+		t.Fatalf("expected a reference to the tree but got %s", toJson(t, schema))
+	}
+
+	if len(schemas) != 1 || !reflect.DeepEqual(schemas["inferTree"], schema) {
+		t.Fatalf("expected the tree schema but got %s", toJson(t, schemas))
+	}
+
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: schemas}
+	if cycles := doc.DetectCycles(); len(cycles) != 1 {
+		t.Fatalf("expected the tree to be a cycle but got %v", cycles)
+	}
+}
+
+func toJson(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
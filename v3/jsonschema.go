@@ -0,0 +1,160 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// JSONSchemaDialect is the $schema of the schemas created by ToJSONSchema.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+const schemasPrefix = "#/components/schemas/"
+
+// ToJSONSchema converts the schema into a standalone JSON Schema (draft 2020-12) document. Each directly or
+// transitively referenced component schema of the document is bundled into $defs and the references are
+// rewritten from #/components/schemas/X to #/$defs/X. A nullable type becomes a type array with null and a
+// nullable without a type is dropped. The boolean exclusiveMinimum and exclusiveMaximum of OAS 3.0 replace the
+// minimum and maximum by their numeric form. The keys of the emitted objects are sorted.
+func (s *Schema) ToJSONSchema(doc *Document) ([]byte, error) {
+	defs := map[string]Schema{}
+	if err := collectSchemaDefs(doc, *s, defs); err != nil {
+		return nil, err
+	}
+
+	root, err := jsonSchemaOf(*s)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := map[string]interface{}{}
+	for name, def := range defs {
+		if converted[name], err = jsonSchemaOf(def); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(`{"$schema":"` + JSONSchemaDialect + `"`)
+	if len(body) > 2 {
+		sb.WriteString(",")
+		sb.Write(body[1 : len(body)-1])
+	}
+
+	if len(converted) > 0 {
+		b, err := json.Marshal(converted)
+		if err != nil {
+			return nil, err
+		}
+		sb.WriteString(`,"$defs":`)
+		sb.Write(b)
+	}
+	sb.WriteString("}")
+
+	return []byte(sb.String()), nil
+}
+
+// jsonSchemaOf returns the generic json representation of the schema with rewritten references, type arrays and
+// numeric exclusive bounds.
+func jsonSchemaOf(s Schema) (interface{}, error) {
+	c := &copier{convert: func(s *Schema) {
+		schemaTo31(s)
+		if s.Ref != nil && strings.HasPrefix(*s.Ref, schemasPrefix) {
+			ref := pointerOf("#/$defs", unescapePointerToken((*s.Ref)[len(schemasPrefix):]))
+			s.Ref = &ref
+		}
+	}}
+
+	v, err := c.copy(reflect.ValueOf(s))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	toDraft2020(tree)
+	return tree, nil
+}
+
+// toDraft2020 replaces the keywords of the generic schema and all of its subschemas, which are valid in OAS 3.0 but
+// not in JSON Schema draft 2020-12.
+func toDraft2020(tree interface{}) {
+	obj, ok := tree.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		if obj[exclusive] != true {
+			continue
+		}
+
+		if value, ok := obj[bound]; ok {
+			obj[exclusive] = value
+			delete(obj, bound)
+		} else {
+			delete(obj, exclusive)
+		}
+	}
+
+	// a nullable type has already become a type array, so the remaining nullable has no type to extend
+	delete(obj, "nullable")
+
+	for _, sub := range subschemas(obj) {
+		toDraft2020(sub)
+	}
+}
+
+// collectSchemaDefs adds all component schemas, which are referenced by the schema, recursively to defs.
+func collectSchemaDefs(doc *Document, s Schema, defs map[string]Schema) error {
+	return walkValue(reflect.ValueOf(s), nil, func(path []string, node interface{}) error {
+		schema, ok := node.(Schema)
+		if !ok || schema.Ref == nil {
+			return nil
+		}
+
+		name, resolved, err := doc.ResolveSchemaRef(*schema.Ref)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := defs[name]; ok {
+			return nil
+		}
+
+		defs[name] = *resolved
+		return collectSchemaDefs(doc, *resolved, defs)
+	})
+}
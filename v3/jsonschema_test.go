@@ -0,0 +1,124 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_toJSONSchema(t *testing.T) {
+	category := "#/components/schemas/Category"
+	name := "#/components/schemas/Name"
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Category": {Type: Object, Properties: map[string]Schema{"name": {Ref: &name}}},
+		"Name":     {Type: String},
+		"Unused":   {Type: Integer},
+	}}
+
+	schema := Schema{
+		Type: Object,
+		Properties: map[string]Schema{
+			"category": {Ref: &category},
+			"nickname": {Type: String, Nullable: true},
+		},
+	}
+
+	b, err := schema.ToJSONSchema(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{` +
+		`"category":{"$ref":"#/$defs/Category"},"nickname":{"type":["string","null"]}},"type":"object","$defs":{` +
+		`"Category":{"properties":{"name":{"$ref":"#/$defs/Name"}},"type":"object"},"Name":{"type":"string"}}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+
+	dangling := "#/components/schemas/Unknown"
+	if _, err := (&Schema{Ref: &dangling}).ToJSONSchema(doc); err == nil {
+		t.Fatal("expected an error for a dangling ref")
+	}
+}
+
+func Test_toJSONSchemaKeywords(t *testing.T) {
+	lower, upper := 1.0, 10.0
+	schema := Schema{
+		Type: Object,
+		Properties: map[string]Schema{
+			"exclusive": {Type: Number, Minimum: &lower, ExclusiveMinimum: true, Maximum: &upper, ExclusiveMaximum: true},
+			"inclusive": {Type: Number, Minimum: &lower, Maximum: &upper},
+			"untyped":   {Nullable: true, Description: "anything"},
+		},
+	}
+
+	b, err := schema.ToJSONSchema(NewDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{` +
+		`"exclusive":{"exclusiveMaximum":10,"exclusiveMinimum":1,"type":"number"},` +
+		`"inclusive":{"maximum":10,"minimum":1,"type":"number"},` +
+		`"untyped":{"description":"anything"}},"type":"object"}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+}
+
+func Test_toJSONSchemaEscapedDefs(t *testing.T) {
+	escaped := "#/components/schemas/pets~1Pet"
+	unescaped := "#/components/schemas/a~b"
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"pets/Pet": {Type: Object},
+		"a~b":      {Type: String},
+	}}
+
+	schema := Schema{Type: Object, Properties: map[string]Schema{"pet": {Ref: &escaped}, "tilde": {Ref: &unescaped}}}
+	b, err := schema.ToJSONSchema(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{` +
+		`"pet":{"$ref":"#/$defs/pets~1Pet"},"tilde":{"$ref":"#/$defs/a~0b"}},"type":"object","$defs":{` +
+		`"a~b":{"type":"string"},"pets/Pet":{"type":"object"}}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+
+	var tree struct {
+		Defs map[string]interface{} `json:"$defs"`
+	}
+	if err := json.Unmarshal(b, &tree); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ref := range []string{"#/$defs/pets~1Pet", "#/$defs/a~0b"} {
+		tokens, err := parsePointer(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := tree.Defs[tokens[1]]; !ok {
+			t.Fatalf("expected %s to resolve within %v", ref, tree.Defs)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Severity classifies a lint result.
+type Severity string
+
+const (
+	ErrorSeverity   Severity = "error"
+	WarningSeverity Severity = "warning"
+	InfoSeverity    Severity = "info"
+)
+
+// A LintResult is a single finding of a LintRule.
+type LintResult struct {
+	Rule     string   // Rule is the name of the rule, which reported the finding, e.g. operation-summary
+	Severity Severity // Severity tells how serious the finding is
+	Location string   // Location is the json pointer of the node, e.g. #/paths/~1pets/get
+	Message  string   // Message describes the finding
+}
+
+// String returns the result in the form <severity>: <location>: <message> (<rule>).
+func (r LintResult) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", r.Severity, r.Location, r.Message, r.Rule)
+}
+
+// A LintRule inspects the document and returns its findings. Rules are plain functions, so that custom rules can
+// be combined with the built-in ones.
+type LintRule func(d *Document) []LintResult
+
+var kebabCaseRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// DefaultLintRules returns the built-in rules, which are applied by Lint, if no rules are given.
+func DefaultLintRules() []LintRule {
+	return []LintRule{OperationSummaryRule, ResponsesRule, KebabCasePathRule, DeclaredTagsRule}
+}
+
+// Lint applies the rules in the given order and returns their findings. Other than Validate, the rules are
+// opinionated style checks and a valid document may still have findings.
+func (d *Document) Lint(rules ...LintRule) []LintResult {
+	if len(rules) == 0 {
+		rules = DefaultLintRules()
+	}
+
+	var r []LintResult
+	for _, rule := range rules {
+		r = append(r, rule(d)...)
+	}
+	return r
+}
+
+// OperationSummaryRule warns about operations without a summary.
+func OperationSummaryRule(d *Document) []LintResult {
+	var r []LintResult
+	for _, ref := range d.Operations() {
+		if strings.TrimSpace(ref.Operation.Summary) == "" {
+			r = append(r, LintResult{
+				Rule:     "operation-summary",
+				Severity: WarningSeverity,
+				Location: pointerOf("paths", ref.Path, strings.ToLower(ref.Method)),
+				Message:  "operation should have a summary",
+			})
+		}
+	}
+	return r
+}
+
+// ResponsesRule reports operations, which do not declare any response.
+func ResponsesRule(d *Document) []LintResult {
+	var r []LintResult
+	for _, ref := range d.Operations() {
+		if len(ref.Operation.Responses) == 0 {
+			r = append(r, LintResult{
+				Rule:     "operation-responses",
+				Severity: ErrorSeverity,
+				Location: pointerOf("paths", ref.Path, strings.ToLower(ref.Method), "responses"),
+				Message:  "operation should declare at least one response",
+			})
+		}
+	}
+	return r
+}
+
+// KebabCasePathRule warns about literal path segments, which are not in kebab-case like /pet-owners/{id}.
+// Template segments are ignored.
+func KebabCasePathRule(d *Document) []LintResult {
+	var r []LintResult
+	for _, path := range sortedKeys(d.Paths) {
+		for _, segment := range strings.Split(path, "/") {
+			if segment == "" || strings.HasPrefix(segment, "{") || kebabCaseRegex.MatchString(segment) {
+				continue
+			}
+
+			r = append(r, LintResult{
+				Rule:     "path-kebab-case",
+				Severity: WarningSeverity,
+				Location: pointerOf("paths", path),
+				Message:  fmt.Sprintf("path segment '%s' should be kebab-case", segment),
+			})
+		}
+	}
+	return r
+}
+
+// DeclaredTagsRule warns about tags, which are used by operations but not declared by the document.
+func DeclaredTagsRule(d *Document) []LintResult {
+	declared := map[string]bool{}
+	for _, tag := range d.Tags {
+		declared[tag.Name] = true
+	}
+
+	var r []LintResult
+	for _, ref := range d.Operations() {
+		for i, tag := range ref.Operation.Tags {
+			if declared[tag] {
+				continue
+			}
+
+			r = append(r, LintResult{
+				Rule:     "operation-tag-defined",
+				Severity: WarningSeverity,
+				Location: pointerOf("paths", ref.Path, strings.ToLower(ref.Method), "tags", fmt.Sprint(i)),
+				Message:  fmt.Sprintf("tag '%s' is not declared", tag),
+			})
+		}
+	}
+	return r
+}
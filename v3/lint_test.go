@@ -0,0 +1,64 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_lintDeclaredTags(t *testing.T) {
+	doc := validDocument()
+	doc.Tags = []Tag{{Name: "pets"}}
+	doc.Paths.Item("/pets/{id}").Get.Tags = []string{"pets", "store"}
+
+	results := doc.Lint(DeclaredTagsRule)
+	expected := []LintResult{{
+		Rule:     "operation-tag-defined",
+		Severity: WarningSeverity,
+		Location: "#/paths/~1pets~1{id}/get/tags/1",
+		Message:  "tag 'store' is not declared",
+	}}
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("expected %v but got %v", expected, results)
+	}
+}
+
+func Test_lintKebabCasePaths(t *testing.T) {
+	doc := validDocument()
+	doc.Paths.Set("/petOwners/{ownerId}/pet-toys", PathItem{})
+
+	results := doc.Lint(KebabCasePathRule)
+	if len(results) != 1 {
+		t.Fatalf("expected a single finding but got %v", results)
+	}
+
+	if s := results[0].String(); s != "warning: #/paths/~1petOwners~1{ownerId}~1pet-toys: path segment 'petOwners' should be kebab-case (path-kebab-case)" {
+		t.Fatalf("unexpected finding %s", s)
+	}
+}
+
+func Test_lintDefaultRules(t *testing.T) {
+	doc := validDocument()
+	doc.Paths.Item("/pets/{id}").Get.Responses = nil
+
+	results := doc.Lint()
+	if len(results) != 2 || results[0].Rule != "operation-summary" || results[1].Severity != ErrorSeverity {
+		t.Fatalf("expected a missing summary and missing responses but got %v", results)
+	}
+}
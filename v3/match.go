@@ -0,0 +1,91 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"sort"
+	"strings"
+)
+
+// MatchPath finds the path item, whose template matches the concrete request path and which declares an
+// operation for the http method, e.g. GET /pets/42 matches /pets/{id} with the params {id: "42"}. An exact
+// template is preferred over a templated one and among templates, the one with more literal segments wins.
+// Trailing slashes are ignored on both sides, so that /pets/ and /pets are equal.
+func (d *Document) MatchPath(method, path string) (template string, item *PathItem, params map[string]string, ok bool) {
+	type candidate struct {
+		template string
+		params   map[string]string
+		literals int
+	}
+
+	var candidates []candidate
+	for _, template := range sortedKeys(d.Paths) {
+		if params, literals, ok := matchTemplate(template, path); ok {
+			candidates = append(candidates, candidate{template: template, params: params, literals: literals})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].literals > candidates[j].literals
+	})
+
+	for _, c := range candidates {
+		pathItem, _ := d.Paths.Get(c.template)
+		if pathItem.Map()[strings.ToUpper(method)] != nil {
+			return c.template, &pathItem, c.params, true
+		}
+	}
+
+	return "", nil, nil, false
+}
+
+// matchTemplate matches each segment of the path against the template, where a {name} segment matches any
+// non-empty value. It returns the parameter values and the amount of literal segments.
+func matchTemplate(template, path string) (map[string]string, int, bool) {
+	templateSegments := strings.Split(trimTrailingSlash(template), "/")
+	pathSegments := strings.Split(trimTrailingSlash(path), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return nil, 0, false
+	}
+
+	params := map[string]string{}
+	literals := 0
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return nil, 0, false
+			}
+			params[segment[1:len(segment)-1]] = pathSegments[i]
+			continue
+		}
+
+		if segment != pathSegments[i] {
+			return nil, 0, false
+		}
+		literals++
+	}
+
+	return params, literals, true
+}
+
+// trimTrailingSlash removes a trailing slash, except for the root path.
+func trimTrailingSlash(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path
+}
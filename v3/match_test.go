@@ -0,0 +1,64 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_matchPath(t *testing.T) {
+	ok := Response{Description: "ok"}
+	doc := NewDocument()
+	doc.Path("/pets").Get().Response(200, ok)
+	doc.Path("/pets/{id}").Get().Response(200, ok).Path().Delete().Response(204, ok)
+	doc.Path("/pets/mine").Get().Response(200, ok)
+	doc.Path("/pets/{id}/toys/{toy}").Get().Response(200, ok)
+
+	tests := []struct {
+		method, path string
+		template     string
+		params       map[string]string
+	}{
+		{"GET", "/pets/42", "/pets/{id}", map[string]string{"id": "42"}},
+		{"GET", "/pets/mine", "/pets/mine", map[string]string{}},
+		{"DELETE", "/pets/mine", "/pets/{id}", map[string]string{"id": "mine"}},
+		{"GET", "/pets/", "/pets", map[string]string{}},
+		{"get", "/pets/42/toys/7/", "/pets/{id}/toys/{toy}", map[string]string{"id": "42", "toy": "7"}},
+	}
+
+	for _, test := range tests {
+		template, item, params, found := doc.MatchPath(test.method, test.path)
+		if !found || template != test.template || item == nil {
+			t.Fatalf("%s %s: expected %s but got %s", test.method, test.path, test.template, template)
+		}
+
+		if !reflect.DeepEqual(params, test.params) {
+			t.Fatalf("%s %s: unexpected params %v", test.method, test.path, params)
+		}
+	}
+
+	for _, path := range []string{"/pets/42/toys", "/owners", "/pets//toys/1"} {
+		if _, _, _, found := doc.MatchPath("GET", path); found {
+			t.Fatalf("expected no match for %s", path)
+		}
+	}
+
+	if _, _, _, found := doc.MatchPath("POST", "/pets"); found {
+		t.Fatal("expected no match for an undeclared method")
+	}
+}
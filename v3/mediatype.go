@@ -0,0 +1,263 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Common media types of request and response bodies.
+const (
+	MediaTypeJSON           = "application/json"
+	MediaTypeFormURLEncoded = "application/x-www-form-urlencoded"
+	MediaTypeMultipart      = "multipart/form-data"
+	MediaTypeOctetStream    = "application/octet-stream"
+	MediaTypeText           = "text/plain"
+)
+
+// MatchMediaType returns true, if the candidate, e.g. a Content-Type, is covered by the pattern, which may be
+// a media range like application/* or */*. Parameters like charset and the case are ignored on both sides.
+func MatchMediaType(pattern, candidate string) bool {
+	return mediaRange{mediaType: baseMediaType(pattern)}.matches(baseMediaType(candidate))
+}
+
+// mediaRange is a single entry of an Accept header, e.g. application/*;q=0.8.
+type mediaRange struct {
+	mediaType string  // mediaType is the lower case type/subtype without parameters
+	quality   float64 // quality is the q-value, which defaults to 1
+	index     int     // index is the position within the header, to prefer the earlier entries
+}
+
+// specificity returns 3 for an exact range, 2 for type/* and 1 for */*.
+func (m mediaRange) specificity() int {
+	switch {
+	case m.mediaType == "*/*":
+		return 1
+	case strings.HasSuffix(m.mediaType, "/*"):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// matches returns true, if the media type is covered by the range.
+func (m mediaRange) matches(mediaType string) bool {
+	switch m.specificity() {
+	case 1:
+		return true
+	case 2:
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(m.mediaType, "*"))
+	default:
+		return m.mediaType == mediaType
+	}
+}
+
+// parseAccept parses the media ranges of an Accept header. An empty header accepts everything.
+func parseAccept(accept string) []mediaRange {
+	if strings.TrimSpace(accept) == "" {
+		return []mediaRange{{mediaType: "*/*", quality: 1}}
+	}
+
+	var ranges []mediaRange
+	for i, entry := range strings.Split(accept, ",") {
+		params := strings.Split(entry, ";")
+		r := mediaRange{mediaType: baseMediaType(params[0]), quality: 1, index: i}
+		if r.mediaType == "" {
+			continue
+		}
+
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 && strings.EqualFold(kv[0], "q") {
+				if q, err := strconv.ParseFloat(kv[1], 64); err == nil {
+					r.quality = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// baseMediaType strips any parameters and normalizes the type/subtype to lower case.
+func baseMediaType(mediaType string) string {
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// SelectMediaType negotiates the best content type of the response for the given Accept header. Each content type
+// gets the q-value of its most specific matching media range, so that e.g. text/*;q=0.5, text/html rates text/html
+// higher than text/plain. On equal q-values, the earlier media range wins. Content types rated with q=0 are never
+// selected. It returns false, if no content type is acceptable.
+func (r Response) SelectMediaType(accept string) (string, *MediaType, bool) {
+	ranges := parseAccept(accept)
+
+	keys := make([]string, 0, len(r.Content))
+	for key := range r.Content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var best *mediaRange
+	bestKey := ""
+	for _, key := range keys {
+		var match *mediaRange
+		for i := range ranges {
+			candidate := &ranges[i]
+			if !candidate.matches(baseMediaType(key)) {
+				continue
+			}
+			if match == nil || candidate.specificity() > match.specificity() {
+				match = candidate
+			}
+		}
+
+		if match == nil || match.quality <= 0 {
+			continue
+		}
+
+		if best == nil || match.quality > best.quality || (match.quality == best.quality && match.index < best.index) {
+			best = match
+			bestKey = key
+		}
+	}
+
+	if best == nil {
+		return "", nil, false
+	}
+
+	mediaType := r.Content[bestKey]
+	return bestKey, &mediaType, true
+}
+
+// SchemaFor returns a copy of the schema, which the response declares for the concrete content type, e.g. of a
+// Content-Type header. Parameters like charset and the case are ignored, so that application/json; charset=utf-8
+// finds application/json. A declared media range like text/* is used, if no more specific content type is declared.
+// It returns false, if no content type matches.
+func (r Response) SchemaFor(contentType string) (*Schema, bool) {
+	if mediaType, ok := r.Content[contentType]; ok {
+		return &mediaType.Schema, true
+	}
+
+	var best *mediaRange
+	bestKey := ""
+	for _, key := range sortedKeys(r.Content) {
+		candidate := mediaRange{mediaType: baseMediaType(key)}
+		if !candidate.matches(baseMediaType(contentType)) {
+			continue
+		}
+
+		if best == nil || candidate.specificity() > best.specificity() {
+			best = &candidate
+			bestKey = key
+		}
+	}
+
+	if best == nil {
+		return nil, false
+	}
+
+	mediaType := r.Content[bestKey]
+	return &mediaType.Schema, true
+}
+
+// ValidateMultipart checks a parsed multipart/form-data body against the object schema and the encodings of the
+// media type. Each required property must be present as a value or a file part. A file part must match the
+// content type of its encoding, which defaults to application/octet-stream for binary strings, application/json
+// for objects and text/plain otherwise, and it must carry the required headers of its encoding. Value parts lose
+// their headers when parsed, so only a json content type is checked by parsing the value. References are not
+// resolved.
+func (m MediaType) ValidateMultipart(form *multipart.Form) []error {
+	var errs []error
+	for _, name := range m.Schema.Required {
+		if len(form.Value[name]) == 0 && len(form.File[name]) == 0 {
+			errs = append(errs, fmt.Errorf("missing required part '%s'", name))
+		}
+	}
+
+	for _, name := range sortedKeys(m.Schema.Properties) {
+		property := m.Schema.Properties[name]
+		encoding := m.Encoding[name]
+		contentTypes := partContentTypes(property, encoding)
+
+		for _, file := range form.File[name] {
+			contentType := baseMediaType(file.Header.Get("Content-Type"))
+			if contentType == "" {
+				contentType = MediaTypeText
+			}
+
+			if !matchesAny(contentTypes, contentType) {
+				errs = append(errs, fmt.Errorf("part '%s' has content type '%s' but expected '%s'", name, contentType, strings.Join(contentTypes, ", ")))
+			}
+
+			for _, header := range sortedKeys(encoding.Headers) {
+				if encoding.Headers[header].Required && file.Header.Get(header) == "" {
+					errs = append(errs, fmt.Errorf("part '%s' misses the required header '%s'", name, header))
+				}
+			}
+		}
+
+		if len(contentTypes) == 1 && strings.HasSuffix(contentTypes[0], "json") {
+			for _, value := range form.Value[name] {
+				if !json.Valid([]byte(value)) {
+					errs = append(errs, fmt.Errorf("part '%s' is not valid json", name))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// partContentTypes returns the declared content types of the encoding or the default of the property schema.
+func partContentTypes(property Schema, encoding Encoding) []string {
+	if encoding.ContentType != "" {
+		var r []string
+		for _, contentType := range strings.Split(encoding.ContentType, ",") {
+			r = append(r, baseMediaType(contentType))
+		}
+		return r
+	}
+
+	switch {
+	case property.Type == String && property.Format == string(Binary):
+		return []string{MediaTypeOctetStream}
+	case property.Type == Object:
+		return []string{MediaTypeJSON}
+	case property.Type == Array && property.Items != nil && property.Items.Schema != nil:
+		return partContentTypes(*property.Items.Schema, Encoding{})
+	default:
+		return []string{MediaTypeText}
+	}
+}
+
+// matchesAny returns true, if any of the media ranges matches the content type.
+func matchesAny(ranges []string, contentType string) bool {
+	for _, r := range ranges {
+		if MatchMediaType(r, contentType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+func Test_selectMediaType(t *testing.T) {
+	resp := Response{
+		Description: "ok",
+		Content: map[string]MediaType{
+			"application/json": {Schema: Schema{Type: Object, Description: "json"}},
+			"application/xml":  {Schema: Schema{Type: Object, Description: "xml"}},
+			"text/plain":       {Schema: Schema{Type: String, Description: "text"}},
+		},
+	}
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"application/xml", "application/xml"},
+		{"application/xml; charset=utf-8", "application/xml"},
+		{"text/*", "text/plain"},
+		{"image/png, */*", "application/json"},
+		{"", "application/json"},
+		{"application/json;q=0.5, application/xml;q=0.9", "application/xml"},
+		{"application/*;q=0.2, text/plain;q=0.8", "text/plain"},
+		{"application/*, application/json;q=0", "application/xml"},
+	}
+
+	for _, test := range tests {
+		contentType, mediaType, ok := resp.SelectMediaType(test.accept)
+		if !ok || contentType != test.want {
+			t.Fatalf("accept '%s': expected %s but got %s", test.accept, test.want, contentType)
+		}
+
+		if mediaType.Schema.Description != resp.Content[test.want].Schema.Description {
+			t.Fatalf("accept '%s': unexpected media type", test.accept)
+		}
+	}
+
+	if _, _, ok := resp.SelectMediaType("image/png"); ok {
+		t.Fatal("expected no acceptable media type")
+	}
+}
+
+func Test_responseSchemaFor(t *testing.T) {
+	resp := Response{
+		Description: "ok",
+		Content: map[string]MediaType{
+			"application/json": {Schema: Schema{Type: Object, Description: "json"}},
+			"text/*":           {Schema: Schema{Type: String, Description: "text"}},
+		},
+	}
+
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"application/json", "json"},
+		{"application/json; charset=utf-8", "json"},
+		{"Application/JSON", "json"},
+		{"text/csv", "text"},
+	}
+
+	for _, test := range tests {
+		schema, ok := resp.SchemaFor(test.contentType)
+		if !ok || schema.Description != test.want {
+			t.Fatalf("content type '%s': expected the %s schema but got %v", test.contentType, test.want, schema)
+		}
+	}
+
+	if _, ok := resp.SchemaFor("application/xml"); ok {
+		t.Fatal("expected no schema")
+	}
+}
+
+func Test_matchMediaType(t *testing.T) {
+	tests := []struct {
+		pattern, candidate string
+		want               bool
+	}{
+		{"application/*", MediaTypeJSON, true},
+		{"*/*", MediaTypeOctetStream, true},
+		{MediaTypeJSON, "application/json; charset=utf-8", true},
+		{"Application/JSON; charset=utf-8", MediaTypeJSON, true},
+		{"application/*", MediaTypeText, false},
+		{MediaTypeJSON, "application/jsonp", false},
+		{"text/*", "text", false},
+	}
+
+	for _, test := range tests {
+		if got := MatchMediaType(test.pattern, test.candidate); got != test.want {
+			t.Fatalf("'%s' matches '%s': expected %v but got %v", test.pattern, test.candidate, test.want, got)
+		}
+	}
+}
+
+func multipartForm(t *testing.T, write func(w *multipart.Writer)) *multipart.Form {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+	write(w)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	form, err := multipart.NewReader(buf, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return form
+}
+
+func jsonPart(t *testing.T, w *multipart.Writer, name, contentType, body string, header map[string]string) {
+	t.Helper()
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s.json"`, name, name))
+	h.Set("Content-Type", contentType)
+	for key, value := range header {
+		h.Set(key, value)
+	}
+
+	part, err := w.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = part.Write([]byte(body))
+}
+
+func Test_validateMultipart(t *testing.T) {
+	media := MediaType{
+		Schema: Schema{
+			Type:     Object,
+			Required: []string{"meta", "file"},
+			Properties: map[string]Schema{
+				"meta":  {Type: Object},
+				"file":  {Type: String, Format: string(Binary)},
+				"title": {Type: String},
+			},
+		},
+		Encoding: map[string]Encoding{
+			"file": {ContentType: "image/png, image/jpeg", Headers: map[string]Header{"X-Checksum": {Required: true}}},
+		},
+	}
+
+	valid := multipartForm(t, func(w *multipart.Writer) {
+		jsonPart(t, w, "meta", "application/json", `{"tags":["cat"]}`, nil)
+		jsonPart(t, w, "file", "image/png", "png", map[string]string{"X-Checksum": "abc"})
+		_ = w.WriteField("title", "Rex")
+	})
+
+	if errs := media.ValidateMultipart(valid); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	invalid := multipartForm(t, func(w *multipart.Writer) {
+		jsonPart(t, w, "meta", "text/xml", `<tags/>`, nil)
+	})
+
+	errs := media.ValidateMultipart(invalid)
+	if len(errs) != 2 || errs[0].Error() != "missing required part 'file'" || !strings.Contains(errs[1].Error(), "'meta' has content type 'text/xml'") {
+		t.Fatalf("unexpected violations %v", errs)
+	}
+
+	wrongFile := multipartForm(t, func(w *multipart.Writer) {
+		_ = w.WriteField("meta", `{"tags":`)
+		jsonPart(t, w, "file", "image/gif", "gif", nil)
+	})
+
+	errs = media.ValidateMultipart(wrongFile)
+	if len(errs) != 3 {
+		t.Fatalf("expected content type, header and json violations but got %v", errs)
+	}
+}
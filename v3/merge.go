@@ -0,0 +1,104 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import "fmt"
+
+// Merge adds the paths and component schemas of the other document to this document, e.g. to combine the
+// specifications of multiple services into a single gateway specification. It is an error if both documents
+// declare the same verb for the same path or schemas with the same name but with different definitions.
+// Equal schemas, see Schema.Equal, are merged silently. In case of an error, the document is not modified.
+func (d *Document) Merge(other *Document) error {
+	for _, path := range sortedKeys(other.Paths) {
+		item, ok := d.Paths.Get(path)
+		if !ok {
+			continue
+		}
+		otherItem, _ := other.Paths.Get(path)
+		for method := range otherItem.Map() {
+			if _, conflict := item.Map()[method]; conflict {
+				return fmt.Errorf("conflicting %s %s", method, path)
+			}
+		}
+	}
+
+	if other.Components != nil && d.Components != nil {
+		for _, name := range sortedKeys(other.Components.Schemas) {
+			schema, ok := d.Components.Schemas[name]
+			if ok && !schema.Equal(other.Components.Schemas[name]) {
+				return fmt.Errorf("conflicting definitions of schema '%s'", name)
+			}
+		}
+	}
+
+	for _, path := range other.Paths.Keys() {
+		otherItem, _ := other.Paths.Get(path)
+		item := d.Paths.Item(path)
+		if item == nil {
+			d.Paths.Set(path, otherItem)
+			continue
+		}
+
+		item.merge(otherItem)
+	}
+
+	if other.Components != nil && len(other.Components.Schemas) > 0 {
+		if d.Components == nil {
+			d.Components = &Components{}
+		}
+		if d.Components.Schemas == nil {
+			d.Components.Schemas = map[string]Schema{}
+		}
+		for name, schema := range other.Components.Schemas {
+			d.Components.Schemas[name] = schema
+		}
+	}
+
+	return nil
+}
+
+// merge takes the operations of the other item, which are not declared by this item. The remaining fields are
+// only taken, if they are not set in this item.
+func (p *PathItem) merge(other PathItem) {
+	if p.Get == nil {
+		p.Get = other.Get
+	}
+	if p.Post == nil {
+		p.Post = other.Post
+	}
+	if p.Delete == nil {
+		p.Delete = other.Delete
+	}
+	if p.Put == nil {
+		p.Put = other.Put
+	}
+	if p.Patch == nil {
+		p.Patch = other.Patch
+	}
+	if p.Summary == "" {
+		p.Summary = other.Summary
+	}
+	if p.Description == "" {
+		p.Description = other.Description
+	}
+	if len(p.Servers) == 0 {
+		p.Servers = other.Servers
+	}
+	if len(p.Parameters) == 0 {
+		p.Parameters = other.Parameters
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import "testing"
+
+func serviceDocument(path string, schemas map[string]Schema) *Document {
+	doc := NewDocument()
+	doc.Path(path).Get().Response(200, Response{Description: "ok"})
+	doc.Components = &Components{Schemas: schemas}
+	return doc
+}
+
+func Test_merge(t *testing.T) {
+	pets := serviceDocument("/pets", map[string]Schema{"Pet": {Type: Object}, "Error": {Type: String}})
+	users := serviceDocument("/users", map[string]Schema{"User": {Type: Object}, "Error": {Type: String}})
+	users.Path("/pets").Post().Response(201, Response{Description: "created"})
+
+	if err := pets.Merge(users); err != nil {
+		t.Fatal(err)
+	}
+
+	if pets.Paths.Len() != 2 || pets.Paths.Item("/pets").Get == nil || pets.Paths.Item("/pets").Post == nil {
+		t.Fatalf("unexpected paths: %+v", pets.Paths)
+	}
+
+	if len(pets.Components.Schemas) != 3 {
+		t.Fatalf("unexpected schemas: %+v", pets.Components.Schemas)
+	}
+}
+
+func Test_mergeConflicts(t *testing.T) {
+	pets := serviceDocument("/pets", map[string]Schema{"Pet": {Type: Object}})
+	if err := pets.Merge(serviceDocument("/pets", nil)); err == nil {
+		t.Fatal("expected a path conflict")
+	}
+
+	if err := pets.Merge(serviceDocument("/dogs", map[string]Schema{"Pet": {Type: String}})); err == nil {
+		t.Fatal("expected a schema conflict")
+	}
+
+	if _, ok := pets.Paths.Get("/dogs"); ok {
+		t.Fatal("expected the document to be unmodified")
+	}
+}
+
+func Test_mergeEqualSchemas(t *testing.T) {
+	parsed, err := FromJson([]byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "pets", "version": "1"},
+		"paths": {},
+		"components": {"schemas": {"Status": {
+			"type": "integer",
+			"enum": [1, 2],
+			"required": ["a", "b"],
+			"x-owner": { "team" : "pets" }
+		}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	built := serviceDocument("/status", map[string]Schema{"Status": {
+		Type:       Integer,
+		Enum:       []interface{}{1, 2},
+		Required:   []string{"b", "a"},
+		Extensions: Extensions{"x-owner": []byte(`{"team":"pets"}`)},
+	}})
+
+	if err := parsed.Merge(built); err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Paths.Len() != 1 || len(parsed.Components.Schemas) != 1 {
+		t.Fatalf("unexpected merge result: %+v", parsed)
+	}
+}
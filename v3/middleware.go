@@ -0,0 +1,325 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NewValidationMiddleware returns a middleware, which validates each request against the matching operation of
+// the document. Required parameters, the types of parameter values and json request bodies are checked and any
+// violation is answered with 400 Bad Request and a plain text body listing each violation per line. Requests
+// which are not declared in the document are passed through unchanged.
+func NewValidationMiddleware(doc *Document) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			errs := doc.validateRequest(r)
+			if len(errs) > 0 {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				w.WriteHeader(http.StatusBadRequest)
+				for _, err := range errs {
+					_, _ = fmt.Fprintln(w, err)
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// validateRequest returns the violations of the request. The body is restored, so that it can be read again.
+func (d *Document) validateRequest(r *http.Request) []error {
+	_, item, pathParams, ok := d.MatchPath(r.Method, r.URL.Path)
+	if !ok {
+		return nil
+	}
+
+	op := item.Map()[strings.ToUpper(r.Method)]
+	if op == nil {
+		return nil
+	}
+
+	// resolve before merging, so that a referenced operation parameter overrides the path parameter
+	resolvedItem, resolvedOp := *item, *op
+	resolvedItem.Parameters = d.resolveParameters(item.Parameters)
+	resolvedOp.Parameters = d.resolveParameters(op.Parameters)
+
+	var errs []error
+	for _, p := range resolvedOp.EffectiveParameters(&resolvedItem) {
+		values := requestParameter(r, p, pathParams)
+		if len(values) == 0 {
+			if p.Required {
+				errs = append(errs, fmt.Errorf("missing required %s parameter '%s'", p.In, p.Name))
+			}
+			continue
+		}
+
+		errs = append(errs, d.validateParameterValues(p, values)...)
+	}
+
+	if body := op.RequestBody; body != nil {
+		if body.Ref != nil {
+			if _, resolved := d.ResolveRequestBodyRef(*body.Ref); resolved != nil {
+				body = resolved
+			}
+		}
+		errs = append(errs, d.validateRequestBody(r, body)...)
+	}
+
+	return errs
+}
+
+// requestParameter returns the raw values of the parameter or nil if absent.
+func requestParameter(r *http.Request, p Parameter, pathParams map[string]string) []string {
+	switch p.In {
+	case PathLocation:
+		if value, ok := pathParams[p.Name]; ok {
+			return []string{value}
+		}
+	case QueryLocation:
+		if p.EffectiveStyle() == DeepObjectStyle {
+			return deepObjectValues(r.URL.Query(), p.Name)
+		}
+		return r.URL.Query()[p.Name]
+	case HeaderLocation:
+		return r.Header.Values(p.Name)
+	case CookieLocation:
+		if cookie, err := r.Cookie(p.Name); err == nil {
+			return []string{cookie.Value}
+		}
+	}
+	return nil
+}
+
+// deepObjectValues returns the values of all name[key] parameters in the order of their keys.
+func deepObjectValues(query url.Values, name string) []string {
+	var values []string
+	for _, key := range sortedKeys(query) {
+		if strings.HasPrefix(key, name+"[") {
+			values = append(values, query[key]...)
+		}
+	}
+	return values
+}
+
+// validateParameterValues checks that the raw values can be parsed as the type of the parameter schema.
+// Non-exploded array values are split at the comma.
+func (d *Document) validateParameterValues(p Parameter, values []string) []error {
+	schema := d.resolveSchema(&p.Schema)
+	itemType := schema.Type
+	if schema.Type == Array {
+		if !p.EffectiveExplode() {
+			values = strings.Split(strings.Join(values, ","), ",")
+		}
+
+		itemType = ""
+		if schema.Items != nil && schema.Items.Schema != nil {
+			itemType = d.resolveSchema(schema.Items.Schema).Type
+		}
+	}
+
+	var errs []error
+	for _, value := range values {
+		if err := parseScalar(itemType, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s parameter '%s': %w", p.In, p.Name, err))
+		}
+	}
+	return errs
+}
+
+// parseScalar checks that the string value is a valid representation of the primitive type.
+func parseScalar(t Type, value string) error {
+	var err error
+	switch t {
+	case Integer:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case Number:
+		_, err = strconv.ParseFloat(value, 64)
+	case Boolean:
+		_, err = strconv.ParseBool(value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid %s", value, t)
+	}
+	return nil
+}
+
+// validateRequestBody checks the presence, the content type and for json media types the payload of the body.
+func (d *Document) validateRequestBody(r *http.Request, body *RequestBody) []error {
+	var payload []byte
+	if r.Body != nil {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return []error{fmt.Errorf("cannot read request body: %w", err)}
+		}
+		_ = r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(b))
+		payload = b
+	}
+
+	if len(payload) == 0 {
+		if body.Required {
+			return []error{fmt.Errorf("missing required request body")}
+		}
+		return nil
+	}
+
+	contentType := baseMediaType(r.Header.Get("Content-Type"))
+	mediaType, ok := requestMediaType(body, contentType)
+	if !ok {
+		return []error{fmt.Errorf("unsupported content type '%s'", contentType)}
+	}
+
+	if !strings.HasSuffix(contentType, "json") {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return []error{fmt.Errorf("invalid json request body: %w", err)}
+	}
+
+	return d.validateJsonValue("body", &mediaType.Schema, value)
+}
+
+// requestMediaType returns the declared media type of the content type. Declared ranges like application/* apply
+// as well.
+func requestMediaType(body *RequestBody, contentType string) (MediaType, bool) {
+	for _, key := range sortedKeys(body.Content) {
+		if baseMediaType(key) == contentType {
+			return body.Content[key], true
+		}
+	}
+
+	for _, key := range sortedKeys(body.Content) {
+		if (mediaRange{mediaType: baseMediaType(key)}).matches(contentType) {
+			return body.Content[key], true
+		}
+	}
+
+	return MediaType{}, false
+}
+
+// validateJsonValue checks the type of the decoded json value, the required properties and recursively the
+// declared properties and items. The constraints of ValidateValue are applied as well.
+func (d *Document) validateJsonValue(loc string, schema *Schema, value interface{}) []error {
+	schema = d.resolveSchema(schema)
+
+	types := schema.Types
+	if len(types) == 0 && schema.Type != "" {
+		types = []Type{schema.Type}
+	}
+
+	if value == nil {
+		if schema.Nullable || len(types) == 0 || containsType(types, Null) {
+			return nil
+		}
+		return []error{fmt.Errorf("%s: must not be null", loc)}
+	}
+
+	if len(types) > 0 && !containsJsonType(types, value) {
+		return []error{fmt.Errorf("%s: expected %s", loc, types[0])}
+	}
+
+	var errs []error
+	for _, err := range schema.ValidateValue(value) {
+		errs = append(errs, fmt.Errorf("%s: %w", loc, err))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, fmt.Errorf("%s: missing required property '%s'", loc, name))
+			}
+		}
+
+		for _, name := range sortedKeys(schema.Properties) {
+			if property, ok := v[name]; ok {
+				propertySchema := schema.Properties[name]
+				errs = append(errs, d.validateJsonValue(loc+"."+name, &propertySchema, property)...)
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i, item := range v {
+				errs = append(errs, d.validateJsonValue(fmt.Sprintf("%s[%d]", loc, i), schema.Items.Schema, item)...)
+			}
+		}
+	}
+
+	return errs
+}
+
+// resolveSchema returns the referenced component schema or the schema itself, if it cannot be resolved.
+func (d *Document) resolveSchema(schema *Schema) *Schema {
+	if schema.Ref != nil {
+		if _, resolved := d.ResolveRef(*schema.Ref); resolved != nil {
+			return resolved
+		}
+	}
+	return schema
+}
+
+// containsJsonType returns true, if the decoded json value is an instance of any of the types.
+func containsJsonType(types []Type, value interface{}) bool {
+	for _, t := range types {
+		switch v := value.(type) {
+		case string:
+			if t == String {
+				return true
+			}
+		case float64:
+			if t == Number || (t == Integer && v == math.Trunc(v)) {
+				return true
+			}
+		case bool:
+			if t == Boolean {
+				return true
+			}
+		case []interface{}:
+			if t == Array {
+				return true
+			}
+		case map[string]interface{}:
+			if t == Object {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsType returns true, if the type is in the list.
+func containsType(types []Type, t Type) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
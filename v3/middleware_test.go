@@ -0,0 +1,188 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func middlewareDocument() *Document {
+	pet, owner := "#/components/schemas/Pet", "#/components/requestBodies/Owner"
+	doc := NewDocument()
+	doc.Path("/pets").
+		Get().
+		Parameter(Parameter{Name: "limit", In: QueryLocation, Required: true, Schema: Schema{Type: Integer}}).
+		Response(http.StatusOK, Response{Description: "ok"}).
+		Path().
+		Post().
+		RequestBody(RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: Schema{Ref: &pet}},
+		}}).
+		Response(http.StatusCreated, Response{Description: "created"})
+	doc.Path("/owners").
+		Post().
+		RequestBody(RequestBody{Ref: &owner}).
+		Response(http.StatusCreated, Response{Description: "created"})
+	doc.Path("/pets/{id}").
+		Parameter(Parameter{Name: "id", In: PathLocation, Required: true, Schema: Schema{Type: Integer}}).
+		Get().
+		Response(http.StatusOK, Response{Description: "ok"})
+
+	doc.Components = &Components{
+		Schemas: map[string]Schema{
+			"Pet": {
+				Type:       Object,
+				Required:   []string{"name"},
+				Properties: map[string]Schema{"name": {Type: String}, "age": {Type: Integer}},
+			},
+		},
+		RequestBodies: map[string]RequestBody{
+			"Owner": {Required: true, Content: map[string]MediaType{"application/json": {Schema: Schema{Ref: &pet}}}},
+		},
+	}
+	return doc
+}
+
+func serveValidated(doc *Document, r *http.Request) *httptest.ResponseRecorder {
+	handler := NewValidationMiddleware(doc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func Test_validationMiddleware(t *testing.T) {
+	doc := middlewareDocument()
+
+	tests := []struct {
+		method, target, body string
+		status               int
+		message              string
+	}{
+		{"GET", "/pets?limit=10", "", http.StatusOK, ""},
+		{"GET", "/pets", "", http.StatusBadRequest, "missing required query parameter 'limit'"},
+		{"GET", "/pets?limit=ten", "", http.StatusBadRequest, "'ten' is not a valid integer"},
+		{"GET", "/pets/42", "", http.StatusOK, ""},
+		{"GET", "/pets/abc", "", http.StatusBadRequest, "path parameter 'id'"},
+		{"POST", "/pets", `{"name":"Rex","age":3}`, http.StatusOK, ""},
+		{"POST", "/pets", "", http.StatusBadRequest, "missing required request body"},
+		{"POST", "/pets", `{"age":"old"}`, http.StatusBadRequest, "missing required property 'name'"},
+		{"POST", "/pets", `{"name":"Rex","age":"old"}`, http.StatusBadRequest, "body.age: expected integer"},
+		{"GET", "/unknown", "", http.StatusOK, ""},
+		{"get", "/pets", "", http.StatusBadRequest, "missing required query parameter 'limit'"},
+		{"PUT", "/pets", "", http.StatusOK, ""},
+		{"POST", "/owners", "", http.StatusBadRequest, "missing required request body"},
+		{"POST", "/owners", `{"age":3}`, http.StatusBadRequest, "missing required property 'name'"},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		r.Header.Set("Content-Type", "application/json")
+
+		w := serveValidated(doc, r)
+		if w.Code != test.status {
+			t.Fatalf("%s %s: expected status %d but got %d: %s", test.method, test.target, test.status, w.Code, w.Body)
+		}
+
+		if !strings.Contains(w.Body.String(), test.message) {
+			t.Fatalf("%s %s: expected '%s' in %s", test.method, test.target, test.message, w.Body)
+		}
+	}
+}
+
+func Test_validationMiddlewareDeepObject(t *testing.T) {
+	doc := NewDocument()
+	doc.Path("/pets").
+		Get().
+		Parameter(Parameter{Name: "filter", In: QueryLocation, Style: DeepObjectStyle, Required: true, Schema: Schema{Type: Object}}).
+		Response(http.StatusOK, Response{Description: "ok"})
+
+	if w := serveValidated(doc, httptest.NewRequest("GET", "/pets?filter[name]=Rex", nil)); w.Code != http.StatusOK {
+		t.Fatalf("expected the deep object to be present but got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveValidated(doc, httptest.NewRequest("GET", "/pets?name=Rex", nil)); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected a missing deep object but got %d", w.Code)
+	}
+}
+
+func Test_validationMiddlewareConcurrent(t *testing.T) {
+	doc := middlewareDocument()
+	pet := doc.Components.Schemas["Pet"]
+	pet.Properties["tags"] = Schema{Type: Array, Items: &Items{Schema: &Schema{Type: String, Pattern: `^[a-z]+$`}}}
+
+	// run with -race to detect validations, which modify the shared document while it is marshalled
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			r := httptest.NewRequest("POST", "/pets", strings.NewReader(`{"name":"Rex","tags":["dog"]}`))
+			r.Header.Set("Content-Type", "application/json")
+			if w := serveValidated(doc, r); w.Code != http.StatusOK {
+				t.Errorf("expected status 200 but got %d: %s", w.Code, w.Body)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if !strings.Contains(doc.String(), `"pattern":"^[a-z]+$"`) {
+				t.Error("expected the pattern in the marshalled document")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_validationMiddlewareParameterRef(t *testing.T) {
+	limit := "#/components/parameters/limit"
+	doc := NewDocument()
+	doc.Path("/pets").
+		Parameter(Parameter{Name: "limit", In: QueryLocation, Required: true, Schema: Schema{Type: String}}).
+		Get().
+		Parameter(Parameter{Ref: &limit}).
+		Response(http.StatusOK, Response{Description: "ok"})
+	doc.Components = &Components{Parameters: map[string]Parameter{
+		"limit": {Name: "limit", In: QueryLocation, Schema: Schema{Type: Integer}},
+	}}
+
+	tests := []struct {
+		target  string
+		status  int
+		message string
+	}{
+		{"/pets?limit=10", http.StatusOK, ""},
+		{"/pets", http.StatusOK, ""},
+		{"/pets?limit=ten", http.StatusBadRequest, "'ten' is not a valid integer"},
+	}
+
+	for _, test := range tests {
+		w := serveValidated(doc, httptest.NewRequest("GET", test.target, nil))
+		if w.Code != test.status {
+			t.Fatalf("%s: expected status %d but got %d: %s", test.target, test.status, w.Code, w.Body)
+		}
+
+		if strings.Count(w.Body.String(), "\n") > 1 || !strings.Contains(w.Body.String(), test.message) {
+			t.Fatalf("%s: expected only '%s' in %s", test.target, test.message, w.Body)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// NewMockServer returns a handler, which answers each declared operation with its success response, see
+// SuccessResponse, and the status code of that response, where a range like 2XX or the default response are
+// answered with 200. The content type is negotiated by the Accept header and the body is the declared example, the
+// first of the named examples or an example generated from the schema. Undeclared paths are answered with
+// 404 Not Found and undeclared methods of a declared path with 405 Method Not Allowed and the Allow header.
+func NewMockServer(doc *Document) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, item, _, ok := doc.MatchPath(r.Method, r.URL.Path)
+		if !ok {
+			if allowed := doc.allowedMethods(r.URL.Path); len(allowed) > 0 {
+				w.Header().Set("Allow", strings.Join(allowed, ", "))
+				http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+				return
+			}
+
+			http.NotFound(w, r)
+			return
+		}
+
+		op := item.Map()[strings.ToUpper(r.Method)]
+		key, ok := op.successResponseKey()
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		status := http.StatusOK
+		if code, err := strconv.Atoi(key); err == nil {
+			status = code
+		}
+
+		resp := op.Responses[key]
+		if resp.Ref != nil {
+			if _, resolved := doc.ResolveResponseRef(*resp.Ref); resolved != nil {
+				resp = *resolved
+			}
+		}
+
+		if len(resp.Content) == 0 {
+			w.WriteHeader(status)
+			return
+		}
+
+		contentType, mediaType, ok := resp.SelectMediaType(r.Header.Get("Accept"))
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+			return
+		}
+
+		body, err := mediaType.mockBody(doc, contentType)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+	})
+}
+
+// allowedMethods returns the declared methods of all path items, whose template matches the path.
+func (d *Document) allowedMethods(path string) []string {
+	declared := map[string]bool{}
+	for _, template := range d.Paths.Keys() {
+		if _, _, ok := matchTemplate(template, path); ok {
+			for method := range d.Paths.Item(template).Map() {
+				declared[method] = true
+			}
+		}
+	}
+
+	var r []string
+	for _, method := range methodOrder {
+		if declared[method] {
+			r = append(r, method)
+		}
+	}
+	return r
+}
+
+// mockBody returns the example of the media type as json or, for a string value of a non-json content type, as
+// is.
+func (m *MediaType) mockBody(doc *Document, contentType string) ([]byte, error) {
+	value := m.Example
+	if value == nil && len(m.Examples) > 0 {
+		value = m.Examples[sortedKeys(m.Examples)[0]].Value
+	}
+
+	if value == nil {
+		generated, err := m.Schema.GenerateExample(doc)
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate an example: %w", err)
+		}
+		value = generated
+	}
+
+	if str, ok := value.(string); ok && !strings.HasSuffix(baseMediaType(contentType), "json") {
+		return []byte(str), nil
+	}
+	return json.Marshal(value)
+}
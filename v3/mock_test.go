@@ -0,0 +1,91 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mockDocument() *Document {
+	doc := NewDocument()
+	doc.Path("/pets").
+		Get().
+		Response(http.StatusOK, Response{Description: "ok", Content: map[string]MediaType{
+			"application/json": {
+				Schema:  Schema{Type: Array, Items: &Items{&Schema{Type: String}}},
+				Example: []string{"Rex", "Bello"},
+			},
+		}}).
+		Path().
+		Post().
+		Response(http.StatusCreated, Response{Description: "created", Content: JSONContent(Schema{
+			Type:       Object,
+			Required:   []string{"id"},
+			Properties: map[string]Schema{"id": {Type: Integer}},
+		})}).
+		Response(http.StatusAccepted, Response{Description: "accepted"})
+	return doc
+}
+
+func serveMock(doc *Document, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	NewMockServer(doc).ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func Test_mockServer(t *testing.T) {
+	doc := mockDocument()
+
+	w := serveMock(doc, "GET", "/pets")
+	if w.Code != http.StatusOK || w.Body.String() != `["Rex","Bello"]` {
+		t.Fatalf("expected the declared example but got %d: %s", w.Code, w.Body.String())
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("unexpected content type %s", contentType)
+	}
+
+	w = serveMock(doc, "POST", "/pets")
+	if w.Code != http.StatusCreated || w.Body.String() != `{"id":0}` {
+		t.Fatalf("expected a generated example with the lowest success status but got %d: %s", w.Code, w.Body.String())
+	}
+
+	delete(doc.Paths.Item("/pets").Post.Responses, "201")
+	w = serveMock(doc, "POST", "/pets")
+	if w.Code != http.StatusAccepted || w.Body.Len() != 0 {
+		t.Fatalf("expected an empty response but got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func Test_mockServerUndeclared(t *testing.T) {
+	doc := mockDocument()
+
+	w := serveMock(doc, "DELETE", "/pets")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 but got %d", w.Code)
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "GET, POST" {
+		t.Fatalf("unexpected Allow header '%s'", allow)
+	}
+
+	if w := serveMock(doc, "GET", "/owners"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 but got %d", w.Code)
+	}
+}
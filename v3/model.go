@@ -17,8 +17,12 @@
 package v3
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/url"
+	"reflect"
 	"strings"
 )
 
@@ -30,6 +34,20 @@ func (u URL) MarshalJSON() ([]byte, error) {
 	return []byte("\"" + u.URL.String() + "\""), nil
 }
 
+// UnmarshalJSON parses the URL from a JSON string.
+func (u *URL) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	r, err := url.Parse(str)
+	if err != nil {
+		return err
+	}
+	u.URL = r
+	return nil
+}
+
 type Location string
 
 const (
@@ -39,36 +57,62 @@ const (
 	CookieLocation Location = "cookie"
 )
 
+// The styles describe how a parameter value is serialized, depending on its location.
+const (
+	MatrixStyle         = "matrix"
+	LabelStyle          = "label"
+	FormStyle           = "form"
+	SimpleStyle         = "simple"
+	SpaceDelimitedStyle = "spaceDelimited"
+	PipeDelimitedStyle  = "pipeDelimited"
+	DeepObjectStyle     = "deepObject"
+)
+
+// styleLocations declares the parameter locations, which support a style.
+var styleLocations = map[string][]Location{
+	MatrixStyle:         {PathLocation},
+	LabelStyle:          {PathLocation},
+	FormStyle:           {QueryLocation, CookieLocation},
+	SimpleStyle:         {PathLocation, HeaderLocation},
+	SpaceDelimitedStyle: {QueryLocation},
+	PipeDelimitedStyle:  {QueryLocation},
+	DeepObjectStyle:     {QueryLocation},
+}
+
 // A Document represents the root of an OpenAPI 3.x.x specification (OAS). The file name for the document should
 // be openapi.json. See also https://github.com/OAI/OpenAPI-Specification/blob/master/versions/3.0.2.md#openapi-object.
 type Document struct {
-	OpenAPI    string              `json:"openapi"`           // OpenAPI version, e.g. 3.0.1 which is required
-	Info       Info                `json:"info"`              // Info contains required metadata about the defined API
-	Servers    []Server            `json:"servers,omitempty"` // Servers contains the target servers or / if empty
-	Paths      map[string]PathItem `json:"paths"`             // Paths contains each endpoint specification
-	Components *Components         `json:"components,omitempty"`
+	OpenAPI      string                 `json:"openapi"`            // OpenAPI version, e.g. 3.0.1 which is required
+	Info         Info                   `json:"info"`               // Info contains required metadata about the defined API
+	Servers      []Server               `json:"servers,omitempty"`  // Servers contains the target servers or / if empty
+	Paths        Paths                  `json:"paths"`              // Paths contains each endpoint specification and is optional in 3.1
+	Webhooks     map[string]PathItem    `json:"webhooks,omitempty"` // Webhooks describes the requests, which the API may send (OAS 3.1)
+	Components   *Components            `json:"components,omitempty"`
+	Security     []SecurityRequirement  `json:"security,omitempty"`     // Security is applied to all operations
+	Tags         []Tag                  `json:"tags,omitempty"`         // Tags declares the order and description of operation tags
+	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty"` // ExternalDocs links to further documentation
+	Extensions   Extensions             `json:"-"`                      // Extensions are the x- fields
 }
 
 // ResolveRef tries to resolve the referenced schema.
-// Currently only searching this document and definitions in components are resolvable.
+// Currently only searching this document and definitions in components are resolvable. Use ResolveSchemaRef
+// to find out, why a reference cannot be resolved.
 func (d *Document) ResolveRef(ref string) (string, *Schema) {
-	prefix := "#/components/schemas/"
-	if strings.HasPrefix(ref, prefix) {
-		name := ref[len(prefix):]
-		if d.Components != nil && d.Components.Schemas != nil {
-			schema, hasSchema := d.Components.Schemas[name]
-			if hasSchema {
-				return name, &schema
-			}
-			return "", nil
-		}
+	name, schema, err := d.ResolveSchemaRef(ref)
+	if err != nil {
+		return "", nil
 	}
-	return "", nil
+	return name, schema
 }
 
 // NewDocument returns a 3.0.n document
 func NewDocument() *Document {
-	return &Document{Paths: map[string]PathItem{}, OpenAPI: "3.0.1"}
+	return &Document{OpenAPI: "3.0.1"}
+}
+
+// NewDocument31 returns a 3.1.n document
+func NewDocument31() *Document {
+	return &Document{OpenAPI: "3.1.0"}
 }
 
 func (d *Document) String() string {
@@ -79,14 +123,60 @@ func (d *Document) String() string {
 	return string(b)
 }
 
+// StringIndent returns the indented json, which is suitable for version control. The paths are emitted in their
+// declaration order and the keys of all maps, like the component schemas, in sorted order, so the output is
+// deterministic.
+func (d *Document) StringIndent() string {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+// WriteTo encodes the document as json into the writer, like String does, and implements io.WriterTo. The json
+// is terminated by a newline.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := json.NewEncoder(cw).Encode(d)
+	return cw.n, err
+}
+
+// countingWriter counts the bytes, which have been written to the delegate.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// A Tag adds metadata to the tag names, which are used by the operations. The order of the tags is used
+// by tools like the Swagger UI for grouping.
+type Tag struct {
+	Name         string                 `json:"name"`                   // Name is required and used by Operation.Tags
+	Description  string                 `json:"description,omitempty"`  // Description is the optional Markdown text
+	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty"` // ExternalDocs links to further documentation
+}
+
+// ExternalDocumentation refers to an external resource for extended documentation.
+type ExternalDocumentation struct {
+	Description string `json:"description,omitempty"` // Description is the optional Markdown text
+	Url         URL    `json:"url"`                   // Url is the required target
+}
+
 // Info describes the API and may be required by some client. It is mainly presented for convenience.
 type Info struct {
-	Title          string  `json:"title"`                    // Title of the specified API and is required
-	Description    string  `json:"description,omitempty"`    // Description is a short Markdown enriched text
-	TermsOfService *URL    `json:"termsOfService,omitempty"` // TermsOfService is an URL or nil
-	Contact        Contact `json:"license,omitempty"`        // Contact to the API maintainer
-	License        License `json:"license,omitempty"`        // License information for the API
-	Version        string  `json:"version"`                  // Version is for the specified API and is required
+	Title          string   `json:"title"`                    // Title of the specified API and is required
+	Summary        string   `json:"summary,omitempty"`        // Summary is a short plain text of OAS 3.1, which is harmless for 3.0 tools
+	Description    string   `json:"description,omitempty"`    // Description is a short Markdown enriched text
+	TermsOfService *URL     `json:"termsOfService,omitempty"` // TermsOfService is an URL or nil
+	Contact        *Contact `json:"contact,omitempty"`        // Contact to the API maintainer or nil
+	License        *License `json:"license,omitempty"`        // License information for the API or nil
+	Version        string   `json:"version"`                  // Version is for the specified API and is required
 }
 
 // Contact contains just some information about the maintainer of the API.
@@ -99,17 +189,103 @@ type Contact struct {
 
 // License describes the license for the described API
 type License struct {
-	Name string `json:"name"`          // Name is the required identifier for the license
-	Url  URL    `json:"url,omitempty"` // Url is an optional url to the license text
+	Name       string `json:"name"`                 // Name is the required identifier for the license
+	Url        URL    `json:"url,omitempty"`        // Url is an optional url to the license text
+	Identifier string `json:"identifier,omitempty"` // Identifier is the SPDX expression of OAS 3.1, e.g. Apache-2.0, exclusive to Url
+}
+
+// MarshalJSON emits all fields but omits the Url, if it is not set.
+func (l License) MarshalJSON() ([]byte, error) {
+	type license License
+	aux := struct {
+		license
+		Url *URL `json:"url,omitempty"`
+	}{license: license(l)}
+
+	if l.Url.URL != nil {
+		aux.Url = &l.Url
+	}
+	return json.Marshal(aux)
 }
 
-// Server represents a service endpoint behind a specific URL
+// Server represents a service endpoint behind a specific URL. The Url is kept as a string, because it is a
+// template, which may contain variables in curly braces like http://localhost:{port}, which are not valid
+// in an URL. Use DefaultURL to get a concrete URL.
 type Server struct {
-	Url         string                    `json:"url"`                   // Url is the required target host
+	Url         string                    `json:"url"`                   // Url is the required target host template
 	Description string                    `json:"description,omitempty"` // Description is the optional Markdown text
 	Variables   map[string]ServerVariable `json:"variables,omitempty"`   // Variables define substitutions for url
 }
 
+// DefaultURL substitutes all variables of the Url template by their default values and parses the result.
+func (s Server) DefaultURL() (URL, error) {
+	str, err := s.Resolve(nil)
+	if err != nil {
+		return URL{}, err
+	}
+
+	u, err := url.Parse(str)
+	if err != nil {
+		return URL{}, err
+	}
+	return URL{u}, nil
+}
+
+// Resolve substitutes all variables of the Url template by the given values. Missing values fall back to the
+// declared defaults. It is an error, if a template variable is not declared or if a value is not contained in
+// the declared enum.
+func (s Server) Resolve(vars map[string]string) (string, error) {
+	return expandServerUrl(s.Url, func(name string) (string, error) {
+		variable, ok := s.Variables[name]
+		if !ok {
+			return "", fmt.Errorf("server variable '%s' is not declared", name)
+		}
+
+		value, ok := vars[name]
+		if !ok {
+			return variable.Default, nil
+		}
+
+		if len(variable.Enum) == 0 {
+			return value, nil
+		}
+
+		for _, allowed := range variable.Enum {
+			if value == allowed {
+				return value, nil
+			}
+		}
+
+		return "", fmt.Errorf("value '%s' of server variable '%s' is not one of %v", value, name, variable.Enum)
+	})
+}
+
+// expandServerUrl replaces each {name} in the template by the value returned from the substitute function.
+func expandServerUrl(template string, substitute func(name string) (string, error)) (string, error) {
+	sb := &strings.Builder{}
+	for {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			sb.WriteString(template)
+			return sb.String(), nil
+		}
+
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unclosed server variable in '%s'", template)
+		}
+
+		value, err := substitute(template[start+1 : start+end])
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(template[:start])
+		sb.WriteString(value)
+		template = template[start+end+1:]
+	}
+}
+
 // ServerVariable represents a Server url substitution rule. Variables in an URL must be declared in curly braces.
 type ServerVariable struct {
 	Enum        []string `json:"enum,omitempty"` // Enum of values for substitution
@@ -119,11 +295,15 @@ type ServerVariable struct {
 
 // A PathItem describes the available operations for a specific path.
 type PathItem struct {
-	Get    *Operation `json:"get,omitempty"`    // Get defines‚ the get Verb
-	Post   *Operation `json:"post,omitempty"`   // Get defines‚ the get Verb
-	Delete *Operation `json:"delete,omitempty"` // Get defines‚ the get Verb
-	Put    *Operation `json:"put,omitempty"`    // Get defines‚ the get Verb
-	Patch  *Operation `json:"patch,omitempty"`  // Get defines‚ the get Verb
+	Summary     string      `json:"summary,omitempty"`     // Summary applies to all operations
+	Description string      `json:"description,omitempty"` // Description is the optional Markdown text
+	Get         *Operation  `json:"get,omitempty"`         // Get defines the GET operation
+	Post        *Operation  `json:"post,omitempty"`        // Post defines the POST operation
+	Delete      *Operation  `json:"delete,omitempty"`      // Delete defines the DELETE operation
+	Put         *Operation  `json:"put,omitempty"`         // Put defines the PUT operation
+	Patch       *Operation  `json:"patch,omitempty"`       // Patch defines the PATCH operation
+	Servers     []Server    `json:"servers,omitempty"`     // Servers overrides the document servers
+	Parameters  []Parameter `json:"parameters,omitempty"`  // Parameters are shared by all operations
 }
 
 func (p *PathItem) Map() map[string]*Operation {
@@ -153,24 +333,267 @@ func (p *PathItem) Map() map[string]*Operation {
 
 // An Operation is the http Verb specifier
 type Operation struct {
-	Tags        []string            `json:"tags,omitempty"`        // Tags are used for logical grouping
-	Summary     string              `json:"summary,omitempty"`     // Summary is a short text for what this is
-	Description string              `json:"description,omitempty"` // Description is like summary but Markdown and longer
-	Parameters  []Parameter         `json:"parameters,omitempty"`  // Parameters for different locations
-	Responses   map[string]Response `json:"responses"`             // Responses is required and defines the results
+	Tags         []string               `json:"tags,omitempty"`         // Tags are used for logical grouping
+	Summary      string                 `json:"summary,omitempty"`      // Summary is a short text for what this is
+	Description  string                 `json:"description,omitempty"`  // Description is like summary but Markdown and longer
+	OperationId  string                 `json:"operationId,omitempty"`  // OperationId is a unique identifier, e.g. for links
+	Parameters   []Parameter            `json:"parameters,omitempty"`   // Parameters for different locations
+	RequestBody  *RequestBody           `json:"requestBody,omitempty"`  // RequestBody is only valid for e.g. POST, PUT and PATCH
+	Responses    map[string]Response    `json:"responses"`              // Responses is required and defines the results
+	Callbacks    map[string]Callback    `json:"callbacks,omitempty"`    // Callbacks are out-of-band requests to the caller
+	Security     []SecurityRequirement  `json:"security,omitempty"`     // Security overrides the document security
+	Deprecated   bool                   `json:"deprecated,omitempty"`   // Deprecated declares that the operation should not be used
+	Servers      []Server               `json:"servers,omitempty"`      // Servers overrides the path and document servers
+	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty"` // ExternalDocs links to further documentation
+	Extensions   Extensions             `json:"-"`                      // Extensions are the x- fields
+}
+
+// MarshalJSON emits the fields and the extensions.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	b, err := json.Marshal(operation(o))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(b, o.Extensions)
+}
+
+// UnmarshalJSON parses the fields and the extensions.
+func (o *Operation) UnmarshalJSON(b []byte) error {
+	type operation Operation
+	if err := json.Unmarshal(b, (*operation)(o)); err != nil {
+		return err
+	}
+
+	ext, err := parseExtensions(b, reflect.TypeOf(*o))
+	o.Extensions = ext
+	return err
+}
+
+// EffectiveSecurity returns the operation security, if declared, otherwise the security of the document.
+func (o *Operation) EffectiveSecurity(doc *Document) []SecurityRequirement {
+	if o.Security != nil {
+		return o.Security
+	}
+	return doc.Security
+}
+
+// A Callback maps runtime expressions like {$request.body#/callbackUrl} to the path item, which describes the
+// request which is sent by the API provider.
+type Callback map[string]PathItem
+
+// RequestBody describes a single request body, e.g. the payload of a POST.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"` // Description is the optional Markdown text
+	Content     map[string]MediaType `json:"content"`               // Content is required and maps media types
+	Required    bool                 `json:"required,omitempty"`    // Required determines if the body is mandatory
+	Ref         *string              `json:"$ref,omitempty"`        // Ref is a reference to a component, e.g. #/components/requestBodies/Pet
+}
+
+// MarshalJSON emits only the reference, if Ref is set. Otherwise all fields are emitted as usual.
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	if r.Ref != nil {
+		return marshalRef(*r.Ref)
+	}
+	type requestBody RequestBody
+	return json.Marshal(requestBody(r))
+}
+
+// EffectiveServers returns the servers which apply to the operation. Operation servers take precedence over the
+// servers of the path item, which in turn take precedence over the document servers. If no servers are declared
+// at all, the default server / is returned. The item may be nil.
+func (o *Operation) EffectiveServers(doc *Document, item *PathItem) []Server {
+	if len(o.Servers) > 0 {
+		return o.Servers
+	}
+	if item != nil && len(item.Servers) > 0 {
+		return item.Servers
+	}
+	if doc != nil && len(doc.Servers) > 0 {
+		return doc.Servers
+	}
+	return []Server{{Url: "/"}}
+}
+
+// EffectiveParameters merges the parameters of the path item with those of the operation. An operation parameter
+// replaces a path parameter with the same name and location. The item may be nil.
+func (o *Operation) EffectiveParameters(item *PathItem) []Parameter {
+	var r []Parameter
+	overridden := map[int]bool{}
+	if item != nil {
+		for _, p := range item.Parameters {
+			for i, op := range o.Parameters {
+				if op.Name == p.Name && op.In == p.In {
+					p = op
+					overridden[i] = true
+					break
+				}
+			}
+			r = append(r, p)
+		}
+	}
+
+	for i, p := range o.Parameters {
+		if !overridden[i] {
+			r = append(r, p)
+		}
+	}
+	return r
 }
 
 // Parameter is used for path, query, header and cookie parameters. It is only unique per name and location.
 type Parameter struct {
-	Name        string               `json:"name"`                 // Name is the required parameter identifier
-	In          Location             `json:"in"`                   // In is the required location specifier
-	Description string               `json:"description"`          // Description is the optional markdown text
-	Required    bool                 `json:"required,omitempty"`   // Required is obligatory for *path* and must be true
-	Deprecated  bool                 `json:"deprecated,omitempty"` // Deprecated declares that it should not be used
-	Schema      Schema               `json:"schema,omitempty"`     // Schema should be used to describe the data type
-	Content     map[string]MediaType `json:"content,omitempty"`    // Content should be used to describe the data type‚
-	// allowEmptyValue is deprecated and should not be used
+	Name            string               `json:"name"`                      // Name is the required parameter identifier
+	In              Location             `json:"in"`                        // In is the required location specifier
+	Description     string               `json:"description"`               // Description is the optional markdown text
+	Required        bool                 `json:"required,omitempty"`        // Required is obligatory for *path* and must be true
+	Deprecated      bool                 `json:"deprecated,omitempty"`      // Deprecated declares that it should not be used
+	AllowEmptyValue bool                 `json:"allowEmptyValue,omitempty"` // AllowEmptyValue permits empty query values like ?flag= but is deprecated
+	Style           string               `json:"style,omitempty"`           // Style of the serialization, see EffectiveStyle for the default
+	Explode         *bool                `json:"explode,omitempty"`         // Explode generates separate parameters for arrays and objects, see EffectiveExplode for the default
+	Schema          Schema               `json:"schema,omitempty"`          // Schema should be used to describe the data type
+	Content         map[string]MediaType `json:"content,omitempty"`         // Content should be used to describe the data type‚
+	Example         interface{}          `json:"example,omitempty"`         // Example is mutually exclusive to Examples
+	Examples        map[string]Example   `json:"examples,omitempty"`        // Examples is mutually exclusive to Example
+	Ref             *string              `json:"$ref,omitempty"`            // Ref is a reference to a component, e.g. #/components/parameters/MyParameter
+	Extensions      Extensions           `json:"-"`                         // Extensions are the x- fields
+	schemaDeclared  bool                 // schemaDeclared is true, if a schema has been parsed, which may also be empty
+}
+
+// Validate checks that not both, an example and examples are declared and that exactly one of a schema or a
+// content with a single media type is declared. A reference is not checked, because its siblings are ignored.
+func (p Parameter) Validate() error {
+	if p.Ref != nil {
+		return nil
+	}
+
+	if p.Example != nil && len(p.Examples) > 0 {
+		return fmt.Errorf("example and examples are mutually exclusive")
+	}
+
+	if p.Style != "" {
+		locations, ok := styleLocations[p.Style]
+		if !ok {
+			return fmt.Errorf("unknown style '%s'", p.Style)
+		}
+
+		if !containsLocation(locations, p.In) {
+			return fmt.Errorf("style '%s' is not allowed for %s parameters", p.Style, p.In)
+		}
+	}
 
+	hasSchema := p.hasSchema()
+	switch {
+	case len(p.Content) > 0 && hasSchema:
+		return fmt.Errorf("schema and content are mutually exclusive")
+	case len(p.Content) > 1:
+		return fmt.Errorf("content must contain exactly one media type")
+	case len(p.Content) == 0 && !hasSchema:
+		return fmt.Errorf("either a schema or a content is required")
+	}
+
+	return nil
+}
+
+// containsLocation returns true, if the location is in the list.
+func containsLocation(list []Location, location Location) bool {
+	for _, candidate := range list {
+		if candidate == location {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveSchema returns the inline schema or the schema of the single media type of the content. It returns
+// false, if the parameter declares neither or is ambiguous.
+func (p Parameter) EffectiveSchema() (*Schema, bool) {
+	hasSchema := p.hasSchema()
+	switch {
+	case hasSchema && len(p.Content) == 0:
+		return &p.Schema, true
+	case !hasSchema && len(p.Content) == 1:
+		for _, mediaType := range p.Content {
+			return &mediaType.Schema, true
+		}
+	}
+	return nil, false
+}
+
+// hasSchema returns true, if the schema is not empty or if an empty schema, which allows any value, has been
+// parsed.
+func (p Parameter) hasSchema() bool {
+	return p.schemaDeclared || !reflect.DeepEqual(p.Schema, Schema{})
+}
+
+// EffectiveStyle returns the declared style or the default of the location, which is form for query and cookie
+// and simple for path and header parameters.
+func (p Parameter) EffectiveStyle() string {
+	if p.Style != "" {
+		return p.Style
+	}
+
+	switch p.In {
+	case QueryLocation, CookieLocation:
+		return FormStyle
+	default:
+		return SimpleStyle
+	}
+}
+
+// EffectiveExplode returns the declared explode flag or the default, which is only true for the form style.
+// E.g. an exploded query array is serialized as ?ids=1&ids=2 and otherwise as ?ids=1,2.
+func (p Parameter) EffectiveExplode() bool {
+	if p.Explode != nil {
+		return *p.Explode
+	}
+	return p.EffectiveStyle() == FormStyle
+}
+
+// MarshalJSON emits only the reference, if Ref is set. Otherwise all fields and extensions are emitted, except
+// an undeclared schema, which would conflict with the content.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	if p.Ref != nil {
+		return marshalRef(*p.Ref)
+	}
+	type parameter Parameter
+	aux := struct {
+		parameter
+		Schema *Schema `json:"schema,omitempty"`
+	}{parameter: parameter(p)}
+
+	if p.hasSchema() {
+		aux.Schema = &p.Schema
+	}
+
+	b, err := json.Marshal(aux)
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(b, p.Extensions)
+}
+
+// UnmarshalJSON parses the fields and the extensions.
+func (p *Parameter) UnmarshalJSON(b []byte) error {
+	type parameter Parameter
+	if err := json.Unmarshal(b, (*parameter)(p)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	_, p.schemaDeclared = fields["schema"]
+
+	ext, err := parseExtensions(b, reflect.TypeOf(*p))
+	p.Extensions = ext
+	return err
+}
+
+// marshalRef emits a reference object, whose siblings must be ignored per specification.
+func marshalRef(ref string) ([]byte, error) {
+	return json.Marshal(map[string]string{"$ref": ref})
 }
 
 // Response specifies a single response from an API endpoint
@@ -178,7 +601,28 @@ type Response struct {
 	Description string               `json:"description"`       // Description is required, for a change
 	Headers     map[string]Header    `json:"headers,omitempty"` // Headers may contain additional information
 	Content     map[string]MediaType `json:"content,omitempty"` // Content describes potential response types
+	Links       map[string]Link      `json:"links,omitempty"`   // Links to operations which can follow this response
+	Ref         *string              `json:"$ref,omitempty"`    // Ref is a reference to a component, e.g. #/components/responses/NotFound
+}
+
+// MarshalJSON emits only the reference, if Ref is set. Otherwise all fields are emitted as usual.
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Ref != nil {
+		return marshalRef(*r.Ref)
+	}
+	type response Response
+	return json.Marshal(response(r))
+}
 
+// A Link describes how values of a response can be used as input for another operation. Either
+// OperationRef or OperationId must be set.
+type Link struct {
+	OperationRef string                 `json:"operationRef,omitempty"` // OperationRef is a relative or absolute reference
+	OperationId  string                 `json:"operationId,omitempty"`  // OperationId is the id of an existing operation
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`   // Parameters are constants or runtime expressions
+	RequestBody  interface{}            `json:"requestBody,omitempty"`  // RequestBody is a constant or runtime expression
+	Description  string                 `json:"description,omitempty"`  // Description is the optional Markdown text
+	Server       *Server                `json:"server,omitempty"`       // Server overrides the target server
 }
 
 // A Reference is a string referring to a component within this document (prefixed with #) or
@@ -187,19 +631,48 @@ type Reference string
 
 // A Header is like a Parameter but without Name and In fields‚
 type Header struct {
-	Description string `json:"description"`          // Description is the optional markdown text
-	Required    bool   `json:"required,omitempty"`   // Required is obligatory for *path* and must be true
-	Deprecated  bool   `json:"deprecated,omitempty"` // Deprecated declares that a parameter should not be used
-	Schema      Schema `json:"schema,omitempty"`     // Schema used to describe the content
+	Description string  `json:"description"`          // Description is the optional markdown text
+	Required    bool    `json:"required,omitempty"`   // Required is obligatory for *path* and must be true
+	Deprecated  bool    `json:"deprecated,omitempty"` // Deprecated declares that a parameter should not be used
+	Schema      Schema  `json:"schema,omitempty"`     // Schema used to describe the content
+	Ref         *string `json:"$ref,omitempty"`       // Ref is a reference to a component, e.g. #/components/headers/X-Rate-Limit
+}
+
+// MarshalJSON emits only the reference, if Ref is set. Otherwise all fields are emitted as usual.
+func (h Header) MarshalJSON() ([]byte, error) {
+	if h.Ref != nil {
+		return marshalRef(*h.Ref)
+	}
+	type header Header
+	return json.Marshal(header(h))
 }
 
 // MediaType provides a schema and an example for it.
 type MediaType struct {
-	Schema Schema `json:"schema"` // Schema is required
-	//	Encoding map[string]Encoding `json:"encoding,omitempty"` // Encoding maps between a property and its encoding.
+	Schema   Schema              `json:"schema"`             // Schema is required
+	Example  interface{}         `json:"example,omitempty"`  // Example is mutually exclusive to Examples
+	Examples map[string]Example  `json:"examples,omitempty"` // Examples is mutually exclusive to Example
+	Encoding map[string]Encoding `json:"encoding,omitempty"` // Encoding maps between a property and its encoding
 }
 
-// An Encoding is applied to a specific schema property.
+// Validate checks that not both, an example and examples are declared.
+func (m MediaType) Validate() error {
+	if m.Example != nil && len(m.Examples) > 0 {
+		return fmt.Errorf("example and examples are mutually exclusive")
+	}
+	return nil
+}
+
+// An Example is a named sample value, either inlined or referenced by an external url.
+type Example struct {
+	Summary       string      `json:"summary,omitempty"`       // Summary is a short description
+	Description   string      `json:"description,omitempty"`   // Description is the optional Markdown text
+	Value         interface{} `json:"value,omitempty"`         // Value is mutually exclusive to ExternalValue
+	ExternalValue string      `json:"externalValue,omitempty"` // ExternalValue is an url to the example
+}
+
+// An Encoding is applied to a specific schema property. It is only applicable for multipart and
+// application/x-www-form-urlencoded request bodies.
 type Encoding struct {
 	ContentType   string            `json:"contentType,omitempty"`   // ContentType like application/json etc
 	Headers       map[string]Header `json:"headers,omitempty"`       // Headers may contain additional information
@@ -210,34 +683,254 @@ type Encoding struct {
 
 // Schema defines a data type or a union of data types.
 type Schema struct {
-	Type          Type              `json:"type,omitempty"`
-	Format        string            `json:"format,omitempty"`        // Format may contain an arbitrary hint for the format
-	Minimum       int64             `json:"minimum,omitempty"`       // Minimum is inclusive
-	Maximum       int64             `json:"maximum,omitempty"`       // Maximum is inclusive
-	MaxLength     int               `json:"maxLength,omitempty"`     // MaxLength in bytes
-	MinLength     int               `json:"minLength,omitempty"`     // MinLength in bytes
-	MaxItems      int               `json:"maxItems,omitempty"`      // MaxItems of an array
-	MinItems      int               `json:"minItems,omitempty"`      // MinItems for an array
-	Nullable      bool              `json:"nullable,omitempty"`      // Nullable allows a null value
-	Pattern       string            `json:"pattern,omitempty"`       // Pattern should be a valid regex
-	Discriminator *Discriminator    `json:"discriminator,omitempty"` // Discriminator allows union types
-	ReadOnly      bool              `json:"readOnly,omitempty"`      // ReadOnly declares a read only property
-	WriteOnly     bool              `json:"writeOnly,omitempty"`     // WriteOnly declares a write only property
-	Deprecated    bool              `json:"deprecated,omitempty"`    // Deprecated, if true should not be used
-	Properties    map[string]Schema `json:"properties,omitempty"`    // Properties is only valid for type Object
-	Ref           *string           `json:"$ref,omitempty"`          // Ref is a reference to another schema, e.g. #/components/schemas/MySchema
-	Items         *Items            `json:"items,omitempty"`
-	Description   string            `json:"description,omitempty"`
-	XType         *string           `json:"x-ee.type,omitempty"`
+	Type                 Type                   `json:"type,omitempty"`
+	Types                []Type                 `json:"-"`                              // Types is the OAS 3.1 type array, e.g. [string null], and takes precedence over Type
+	Title                string                 `json:"title,omitempty"`                // Title is a short human readable label of the type
+	Format               string                 `json:"format,omitempty"`               // Format may contain an arbitrary hint for the format
+	Minimum              *float64               `json:"minimum,omitempty"`              // Minimum is inclusive, nil if unset
+	Maximum              *float64               `json:"maximum,omitempty"`              // Maximum is inclusive, nil if unset
+	ExclusiveMinimum     bool                   `json:"exclusiveMinimum,omitempty"`     // ExclusiveMinimum excludes Minimum (OAS 3.0 form)
+	ExclusiveMaximum     bool                   `json:"exclusiveMaximum,omitempty"`     // ExclusiveMaximum excludes Maximum (OAS 3.0 form)
+	MultipleOf           *float64               `json:"multipleOf,omitempty"`           // MultipleOf must be greater than 0, nil if unset
+	MaxLength            int                    `json:"maxLength,omitempty"`            // MaxLength in bytes
+	MinLength            int                    `json:"minLength,omitempty"`            // MinLength in bytes
+	MaxItems             int                    `json:"maxItems,omitempty"`             // MaxItems of an array
+	MinItems             int                    `json:"minItems,omitempty"`             // MinItems for an array
+	UniqueItems          bool                   `json:"uniqueItems,omitempty"`          // UniqueItems requires distinct array items
+	MaxProperties        *int                   `json:"maxProperties,omitempty"`        // MaxProperties of an object, nil if unset
+	MinProperties        *int                   `json:"minProperties,omitempty"`        // MinProperties of an object, nil if unset
+	Nullable             bool                   `json:"nullable,omitempty"`             // Nullable allows a null value
+	Pattern              string                 `json:"pattern,omitempty"`              // Pattern should be a valid regex
+	Enum                 []interface{}          `json:"enum,omitempty"`                 // Enum restricts the value to the listed ones
+	Const                interface{}            `json:"const,omitempty"`                // Const is the only valid value (OAS 3.1) and only omitted if nil, so false or 0 are kept
+	Default              interface{}            `json:"default,omitempty"`              // Default is only omitted if nil, so false or 0 are kept
+	Example              interface{}            `json:"example,omitempty"`              // Example is a free-form sample value
+	Discriminator        *Discriminator         `json:"discriminator,omitempty"`        // Discriminator allows union types
+	AllOf                []Schema               `json:"allOf,omitempty"`                // AllOf requires all schemas to be valid
+	OneOf                []Schema               `json:"oneOf,omitempty"`                // OneOf requires exactly one schema to be valid
+	AnyOf                []Schema               `json:"anyOf,omitempty"`                // AnyOf requires at least one schema to be valid
+	Not                  *Schema                `json:"not,omitempty"`                  // Not requires the schema to be invalid
+	ReadOnly             bool                   `json:"readOnly,omitempty"`             // ReadOnly declares a read only property
+	WriteOnly            bool                   `json:"writeOnly,omitempty"`            // WriteOnly declares a write only property
+	Deprecated           bool                   `json:"deprecated,omitempty"`           // Deprecated, if true should not be used
+	Properties           map[string]Schema      `json:"properties,omitempty"`           // Properties is only valid for type Object
+	AdditionalProperties *AdditionalProperties  `json:"additionalProperties,omitempty"` // AdditionalProperties describes map values
+	Required             []string               `json:"required,omitempty"`             // Required lists the mandatory properties of an Object
+	Ref                  *string                `json:"$ref,omitempty"`                 // Ref is a reference to another schema, e.g. #/components/schemas/MySchema
+	Items                *Items                 `json:"items,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	ExternalDocs         *ExternalDocumentation `json:"externalDocs,omitempty"` // ExternalDocs links to further documentation
+	XType                *string                `json:"x-ee.type,omitempty"`
+	Extensions           Extensions             `json:"-"` // Extensions are the x- fields besides x-ee.type
 }
 
 type Items struct {
 	*Schema
 }
 
-// Components defines various central specifications
+// ElementSchema returns the schema of the array elements, where a referenced items schema is resolved against
+// the document. Only a single level of references is followed. It returns false, if the schema is not an array,
+// has no items or the reference cannot be resolved.
+func (s *Schema) ElementSchema(doc *Document) (*Schema, bool) {
+	if s.Type != Array && !containsType(s.Types, Array) {
+		return nil, false
+	}
+
+	if s.Items == nil || s.Items.Schema == nil {
+		return nil, false
+	}
+
+	if s.Items.Ref == nil {
+		return s.Items.Schema, true
+	}
+
+	_, resolved := doc.ResolveRef(*s.Items.Ref)
+	return resolved, resolved != nil
+}
+
+// IsRequired returns true, if the object schema lists the property as required. A schema without a type but with
+// properties is an object as well. It returns false for other schemas, e.g. arrays.
+func (s *Schema) IsRequired(property string) bool {
+	isObject := s.Type == Object || containsType(s.Types, Object) || (s.Type == "" && len(s.Types) == 0 && len(s.Properties) > 0)
+	return isObject && containsString(s.Required, property)
+}
+
+// MarshalJSON emits the schema or an empty schema object.
+func (i Items) MarshalJSON() ([]byte, error) {
+	if i.Schema == nil {
+		return []byte("{}"), nil
+	}
+	return i.Schema.MarshalJSON()
+}
+
+// UnmarshalJSON allocates the schema before it is parsed.
+func (i *Items) UnmarshalJSON(b []byte) error {
+	i.Schema = &Schema{}
+	return i.Schema.UnmarshalJSON(b)
+}
+
+// MarshalJSON emits the Types as the type array, if present, and the extensions.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type schema Schema
+	if len(s.Types) > 0 {
+		// the Types take precedence, so the Type must not be emitted as a second type key
+		s.Type = ""
+	}
+
+	b, err := json.Marshal(schema(s))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Types) == 0 {
+		return appendExtensions(b, s.Extensions)
+	}
+
+	types, err := json.Marshal(s.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	// insert the array at the position of the omitted Type
+	b = b[1:]
+	if len(b) > 1 {
+		types = append(types, ',')
+	}
+	return appendExtensions(append(append([]byte(`{"type":`), types...), b...), s.Extensions)
+}
+
+// UnmarshalJSON parses a type array into Types and a single type into Type and the extensions.
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	type schema Schema
+	aux := struct {
+		*schema
+		Type json.RawMessage `json:"type,omitempty"`
+	}{schema: (*schema)(s)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	ext, err := parseExtensions(b, reflect.TypeOf(*s))
+	if err != nil {
+		return err
+	}
+	s.Extensions = ext
+
+	raw := bytes.TrimSpace(aux.Type)
+	switch {
+	case len(raw) == 0:
+		return nil
+	case raw[0] == '[':
+		return json.Unmarshal(raw, &s.Types)
+	default:
+		return json.Unmarshal(raw, &s.Type)
+	}
+}
+
+// AdditionalProperties is either a boolean or a schema, which describes the values of a map-like object.
+// If both are set, the Schema takes precedence.
+type AdditionalProperties struct {
+	Bool   *bool   // Bool allows (true) or forbids (false) any additional property
+	Schema *Schema // Schema describes the type of the additional properties
+}
+
+// MarshalJSON emits either the schema object or the boolean.
+func (a AdditionalProperties) MarshalJSON() ([]byte, error) {
+	if a.Schema != nil {
+		return json.Marshal(a.Schema)
+	}
+	if a.Bool != nil {
+		return json.Marshal(*a.Bool)
+	}
+	return []byte("true"), nil
+}
+
+// UnmarshalJSON accepts either a boolean or a schema object.
+func (a *AdditionalProperties) UnmarshalJSON(b []byte) error {
+	var flag bool
+	if err := json.Unmarshal(b, &flag); err == nil {
+		a.Bool = &flag
+		a.Schema = nil
+		return nil
+	}
+
+	schema := &Schema{}
+	if err := json.Unmarshal(b, schema); err != nil {
+		return err
+	}
+	a.Bool = nil
+	a.Schema = schema
+	return nil
+}
+
+// Components defines various central specifications, which can be referenced using $ref, e.g.
+// #/components/parameters/MyParameter.
 type Components struct {
-	Schemas map[string]Schema `json:"schemas,omitempty"`
+	Schemas         map[string]Schema         `json:"schemas,omitempty"`
+	Responses       map[string]Response       `json:"responses,omitempty"`
+	Parameters      map[string]Parameter      `json:"parameters,omitempty"`
+	Examples        map[string]Example        `json:"examples,omitempty"`
+	RequestBodies   map[string]RequestBody    `json:"requestBodies,omitempty"`
+	Headers         map[string]Header         `json:"headers,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	Links           map[string]Link           `json:"links,omitempty"`
+	Callbacks       map[string]Callback       `json:"callbacks,omitempty"`
+}
+
+// SecuritySchemeType is the kind of a SecurityScheme.
+type SecuritySchemeType string
+
+const (
+	APIKeySecurity        SecuritySchemeType = "apiKey"
+	HTTPSecurity          SecuritySchemeType = "http"
+	OAuth2Security        SecuritySchemeType = "oauth2"
+	OpenIDConnectSecurity SecuritySchemeType = "openIdConnect"
+)
+
+// A SecurityScheme defines an authentication mechanism which can be used by the operations.
+// Depending on the Type, only a subset of the fields is applicable.
+type SecurityScheme struct {
+	Type             SecuritySchemeType `json:"type"`                       // Type is required
+	Description      string             `json:"description,omitempty"`      // Description is the optional Markdown text
+	Name             string             `json:"name,omitempty"`             // Name of the header, query or cookie for apiKey
+	In               Location           `json:"in,omitempty"`               // In is the location of the apiKey
+	Scheme           string             `json:"scheme,omitempty"`           // Scheme for http, e.g. basic or bearer
+	BearerFormat     string             `json:"bearerFormat,omitempty"`     // BearerFormat is a hint like JWT
+	Flows            *OAuthFlows        `json:"flows,omitempty"`            // Flows are required for oauth2
+	OpenIdConnectUrl *URL               `json:"openIdConnectUrl,omitempty"` // OpenIdConnectUrl is required for openIdConnect
+}
+
+// OAuthFlows configures the supported OAuth2 flows.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`          // Implicit flow
+	Password          *OAuthFlow `json:"password,omitempty"`          // Password is the Resource Owner Password flow
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"` // ClientCredentials is the application flow
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"` // AuthorizationCode is the access code flow
+}
+
+// A SecurityRequirement maps the names of security schemes to the required scopes. The scopes are empty for
+// non-oauth2 schemes. An empty requirement makes the security optional.
+type SecurityRequirement map[string][]string
+
+// MarshalJSON always emits an object and lists, because a nil requirement means optional security.
+func (r SecurityRequirement) MarshalJSON() ([]byte, error) {
+	tmp := make(map[string][]string, len(r))
+	for name, scopes := range r {
+		if scopes == nil {
+			scopes = []string{}
+		}
+		tmp[name] = scopes
+	}
+	return json.Marshal(tmp)
+}
+
+// An OAuthFlow contains the details of a single OAuth2 flow.
+type OAuthFlow struct {
+	AuthorizationUrl *URL              `json:"authorizationUrl,omitempty"` // AuthorizationUrl for implicit and authorizationCode
+	TokenUrl         *URL              `json:"tokenUrl,omitempty"`         // TokenUrl for password, clientCredentials and authorizationCode
+	RefreshUrl       *URL              `json:"refreshUrl,omitempty"`       // RefreshUrl is optional
+	Scopes           map[string]string `json:"scopes"`                     // Scopes maps the scope names to a short description
 }
 
 // Type of a schema, see https://swagger.io/docs/specification/data-models/data-types/
@@ -247,11 +940,25 @@ const (
 	String  Type = "string"
 	Number  Type = "number"
 	Integer Type = "integer"
-	Boolean Type = "bool"
+	Boolean Type = "boolean"
 	Array   Type = "array"
 	Object  Type = "object"
+	Null    Type = "null" // Null is only valid in OAS 3.1 type arrays
 )
 
+// UnmarshalJSON also accepts the invalid "bool", which has been emitted by former versions for Boolean.
+func (t *Type) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	if str == "bool" {
+		str = string(Boolean)
+	}
+	*t = Type(str)
+	return nil
+}
+
 // Format hint, may be anything, e.g. Regex
 type Format string
 
@@ -267,6 +974,44 @@ const (
 	Password Format = "password"
 )
 
+// formatTypes declares the types, which are allowed for the known formats.
+var formatTypes = map[Format][]Type{
+	Int32:    {Integer},
+	Int64:    {Integer},
+	Float:    {Number},
+	Double:   {Number},
+	Binary:   {String},
+	Byte:     {String},
+	Date:     {String},
+	DateTime: {String},
+	Password: {String},
+}
+
+// ValidateFormat checks that the known formats of the schema and its subschemas are used with their types, e.g.
+// that int32 is only used with integer and date-time only with string. Unknown formats and schemas without a
+// type are accepted. The errors are located by json pointers relative to the schema. References are not resolved.
+func (s *Schema) ValidateFormat() []error {
+	var errs []error
+	s.Walk(func(path string, sub *Schema) {
+		allowed, ok := formatTypes[Format(sub.Format)]
+		if !ok {
+			return
+		}
+
+		types := sub.Types
+		if len(types) == 0 && sub.Type != "" {
+			types = []Type{sub.Type}
+		}
+
+		for _, t := range types {
+			if t != Null && !containsType(allowed, t) {
+				errs = append(errs, fmt.Errorf("%s: format '%s' requires type %s but got %s", path, sub.Format, allowed[0], t))
+			}
+		}
+	})
+	return errs
+}
+
 // A Discriminator specifies a field which maps between values and (polymorphic) types.
 type Discriminator struct {
 	PropertyName string            `json:"propertyName"`      // The required field name
@@ -282,3 +1027,13 @@ func FromJson(str []byte) (*Document, error) {
 	}
 	return doc, nil
 }
+
+// FromReader parses the json document from the reader, e.g. a file or a http response body, without reading it
+// into memory first.
+func FromReader(r io.Reader) (*Document, error) {
+	doc := &Document{}
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return doc, fmt.Errorf("cannot decode document: %w", err)
+	}
+	return doc, nil
+}
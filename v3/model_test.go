@@ -17,9 +17,13 @@
 package v3
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -32,26 +36,28 @@ func mustParse(s string) URL {
 }
 
 func Test_model(t *testing.T) {
+	termsOfService := mustParse("https://raw.githubusercontent.com/ee4g/openapi/master/LICENSE")
+	contactUrl := mustParse("https://github.com/torbenschinke")
 	spec := Document{
 		OpenAPI: "3.0.1",
 		Info: Info{
 			Title:          "Demo API",
 			Description:    "Short summary of the Demo API",
-			TermsOfService: mustParse("https://raw.githubusercontent.com/ee4g/openapi/master/LICENSE"),
-			Contact: Contact{
+			TermsOfService: &termsOfService,
+			Contact: &Contact{
 				Name:  "Torben Schinke",
-				Url:   mustParse("https://github.com/torbenschinke"),
+				Url:   &contactUrl,
 				Email: "tschinke@localhost",
 			},
-			License: License{
+			License: &License{
 				Name: "Apache 2",
-				Url:  mustParse("https://raw.githubusercontent.com/ee4g/openapi/master/LICENSE"),
+				Url:  termsOfService,
 			},
 			Version: "0.0.1",
 		},
 		Servers: []Server{
 			{
-				Url:         mustParse("localhost:{port}"),
+				Url:         "localhost:{port}",
 				Description: "For your local development experience",
 				Variables: map[string]ServerVariable{
 					"port": {
@@ -62,11 +68,12 @@ func Test_model(t *testing.T) {
 				},
 			},
 		},
-		Paths: map[string]PathItem{
-			"/auth/session": {
+		Paths: NewPaths(PathEntry{
+			Path: "/auth/session",
+			Item: PathItem{
 				Summary:     "Authentication",
 				Description: "The Session endpoint‚",
-				Get: Operation{
+				Get: &Operation{
 					Tags:        []string{"Tag A", "Tag B"},
 					Summary:     "A summary for the GET session",
 					Description: "A more *lengthy* text for the description of GET",
@@ -100,7 +107,7 @@ func Test_model(t *testing.T) {
 					},
 				},
 			},
-		},
+		}),
 	}
 
 	b, err := json.MarshalIndent(spec, " ", " ")
@@ -109,3 +116,1102 @@ func Test_model(t *testing.T) {
 	}
 	fmt.Println(string(b))
 }
+
+func Test_requestBody(t *testing.T) {
+	op := Operation{Responses: map[string]Response{"204": {Description: "no content"}}}
+	b, err := json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "requestBody") {
+		t.Fatalf("expected requestBody to be omitted: %s", b)
+	}
+
+	doc, err := FromJson([]byte(`{
+		"openapi": "3.0.1",
+		"info": {"title": "Demo API", "version": "0.0.1"},
+		"paths": {
+			"/pets": {
+				"post": {
+					"requestBody": {
+						"description": "the pet to add",
+						"required": true,
+						"content": {"application/json": {"schema": {"type": "object"}}}
+					},
+					"responses": {"201": {"description": "created"}}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := doc.Paths.Item("/pets").Post.RequestBody
+	if body == nil || !body.Required || body.Description != "the pet to add" {
+		t.Fatalf("unexpected request body: %+v", body)
+	}
+	if body.Content["application/json"].Schema.Type != Object {
+		t.Fatalf("unexpected content: %+v", body.Content)
+	}
+
+	b, err = json.Marshal(doc.Paths.Item("/pets").Post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"requestBody":{"description":"the pet to add","content":{"application/json":{"schema":{"type":"object"}}},"required":true}`) {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_securitySchemes(t *testing.T) {
+	tokenUrl := mustParse("https://example.com/token")
+	components := Components{
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearer": {
+				Type:         HTTPSecurity,
+				Scheme:       "bearer",
+				BearerFormat: "JWT",
+			},
+			"key": {
+				Type: APIKeySecurity,
+				Name: "X-API-Key",
+				In:   HeaderLocation,
+			},
+			"oauth": {
+				Type: OAuth2Security,
+				Flows: &OAuthFlows{
+					ClientCredentials: &OAuthFlow{
+						TokenUrl: &tokenUrl,
+						Scopes:   map[string]string{"read": "read access"},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(components)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"securitySchemes":{` +
+		`"bearer":{"type":"http","scheme":"bearer","bearerFormat":"JWT"},` +
+		`"key":{"type":"apiKey","name":"X-API-Key","in":"header"},` +
+		`"oauth":{"type":"oauth2","flows":{"clientCredentials":{"tokenUrl":"https://example.com/token","scopes":{"read":"read access"}}}}}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+}
+
+func Test_securityRequirement(t *testing.T) {
+	doc := NewDocument()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1", License: &License{Name: "MIT", Url: mustParse("https://opensource.org/licenses/MIT")}}
+	doc.Components = &Components{
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearer": {Type: HTTPSecurity, Scheme: "bearer"},
+		},
+	}
+	doc.Security = []SecurityRequirement{{"bearer": nil}}
+	doc.Paths.Set("/health", PathItem{
+		Get: &Operation{
+			Security:  []SecurityRequirement{{}},
+			Responses: map[string]Response{"200": {Description: "ok"}},
+		},
+	})
+	doc.Paths.Set("/me", PathItem{
+		Get: &Operation{
+			Responses: map[string]Response{"200": {Description: "ok"}},
+		},
+	})
+
+	str := doc.String()
+	if !strings.Contains(str, `"security":[{"bearer":[]}]`) {
+		t.Fatalf("expected global bearer requirement: %s", str)
+	}
+	if !strings.Contains(str, `"security":[{}]`) {
+		t.Fatalf("expected empty operation requirement: %s", str)
+	}
+
+	if sec := doc.Paths.Item("/health").Get.EffectiveSecurity(doc); len(sec) != 1 || len(sec[0]) != 0 {
+		t.Fatalf("expected optional security but got %v", sec)
+	}
+	sec := doc.Paths.Item("/me").Get.EffectiveSecurity(doc)
+	if _, ok := sec[0]["bearer"]; len(sec) != 1 || !ok {
+		t.Fatalf("expected bearer security but got %v", sec)
+	}
+}
+
+func Test_booleanType(t *testing.T) {
+	b, err := json.Marshal(Schema{Type: Boolean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"boolean"}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{"type":"bool"}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Type != Boolean {
+		t.Fatalf("expected legacy bool to be migrated but got %s", schema.Type)
+	}
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func Test_minimumMaximum(t *testing.T) {
+	b, err := json.Marshal(Schema{Type: Number, Minimum: float64Ptr(0), Maximum: float64Ptr(99.99)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"number","minimum":0,"maximum":99.99}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Minimum == nil || *schema.Minimum != 0 || schema.Maximum == nil || *schema.Maximum != 99.99 {
+		t.Fatalf("unexpected bounds: %v %v", schema.Minimum, schema.Maximum)
+	}
+}
+
+func Test_exclusiveBounds(t *testing.T) {
+	b, err := json.Marshal(Schema{Type: Number, Minimum: float64Ptr(0), ExclusiveMinimum: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"number","minimum":0,"exclusiveMinimum":true}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_multipleOf(t *testing.T) {
+	doc, err := FromJson([]byte(`{
+		"openapi": "3.0.1",
+		"info": {"title": "Demo API", "version": "0.0.1"},
+		"paths": {},
+		"components": {"schemas": {"Amount": {"type": "number", "multipleOf": 0.5}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := doc.Components.Schemas["Amount"]
+	if schema.MultipleOf == nil || *schema.MultipleOf != 0.5 {
+		t.Fatalf("unexpected multipleOf: %v", schema.MultipleOf)
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"number","multipleOf":0.5}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_enum(t *testing.T) {
+	b, err := json.Marshal(Schema{Type: String, Enum: []interface{}{"active", "inactive"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"string","enum":["active","inactive"]}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	b, err = json.Marshal(Schema{Enum: []interface{}{"1", 1, true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"enum":["1",1,true]}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_default(t *testing.T) {
+	b, err := json.Marshal(Schema{Type: Boolean, Default: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"boolean","default":false}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Default != false {
+		t.Fatalf("expected default false but got %v", schema.Default)
+	}
+
+	b, err = json.Marshal(Schema{Type: Boolean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"boolean"}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_examples(t *testing.T) {
+	content := map[string]MediaType{
+		"application/json": {
+			Schema:  Schema{Type: Object, Example: map[string]interface{}{"id": 1}},
+			Example: map[string]interface{}{"id": 1, "name": "Bello"},
+		},
+		"application/xml": {
+			Schema: Schema{Type: Object},
+			Examples: map[string]Example{
+				"dog": {Summary: "A dog", Value: "pet 1"},
+				"cat": {Summary: "A cat", ExternalValue: "https://example.com/cat.xml"},
+			},
+		},
+	}
+
+	b, err := json.Marshal(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"application/json":{"schema":{"type":"object","example":{"id":1}},"example":{"id":1,"name":"Bello"}},` +
+		`"application/xml":{"schema":{"type":"object"},"examples":{` +
+		`"cat":{"summary":"A cat","externalValue":"https://example.com/cat.xml"},` +
+		`"dog":{"summary":"A dog","value":"pet 1"}}}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+
+	for _, mediaType := range content {
+		if err := mediaType.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	invalid := MediaType{Example: "a", Examples: map[string]Example{"b": {Value: "b"}}}
+	if err := invalid.Validate(); err == nil {
+		t.Fatal("expected example and examples to be mutually exclusive")
+	}
+}
+
+func Test_composition(t *testing.T) {
+	cat := "#/components/schemas/Cat"
+	dog := "#/components/schemas/Dog"
+	schema := Schema{
+		OneOf: []Schema{{Ref: &cat}, {Ref: &dog}},
+		Discriminator: &Discriminator{
+			PropertyName: "petType",
+			Mapping:      map[string]string{"cat": cat, "dog": dog},
+		},
+		Not: &Schema{Type: String},
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"discriminator":{"propertyName":"petType","mapping":{"cat":"#/components/schemas/Cat","dog":"#/components/schemas/Dog"}},` +
+		`"oneOf":[{"$ref":"#/components/schemas/Cat"},{"$ref":"#/components/schemas/Dog"}],"not":{"type":"string"}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+}
+
+func Test_additionalProperties(t *testing.T) {
+	forbidden := false
+	tests := []struct {
+		schema   Schema
+		expected string
+	}{
+		{
+			schema:   Schema{Type: Object, AdditionalProperties: &AdditionalProperties{Bool: &forbidden}},
+			expected: `{"type":"object","additionalProperties":false}`,
+		},
+		{
+			schema:   Schema{Type: Object, AdditionalProperties: &AdditionalProperties{Schema: &Schema{Type: Integer}}},
+			expected: `{"type":"object","additionalProperties":{"type":"integer"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.expected {
+			t.Fatalf("expected\n%s\nbut got\n%s", tt.expected, b)
+		}
+
+		var schema Schema
+		if err := json.Unmarshal(b, &schema); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(schema, tt.schema) {
+			t.Fatalf("expected\n%+v\nbut got\n%+v", tt.schema, schema)
+		}
+	}
+}
+
+func Test_requiredProperties(t *testing.T) {
+	schema := Schema{
+		Type: Object,
+		Properties: map[string]Schema{
+			"id":   {Type: Integer},
+			"name": {Type: String},
+		},
+		Required: []string{"id"},
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"}},"required":["id"]}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_tags(t *testing.T) {
+	doc := NewDocument()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1", License: &License{Name: "MIT", Url: mustParse("https://opensource.org/licenses/MIT")}}
+	doc.Tags = []Tag{
+		{Name: "pets", Description: "Everything about pets"},
+		{Name: "auth", Description: "Authentication", ExternalDocs: &ExternalDocumentation{Url: mustParse("https://example.com/auth")}},
+	}
+
+	expected := `"tags":[{"name":"pets","description":"Everything about pets"},` +
+		`{"name":"auth","description":"Authentication","externalDocs":{"url":"https://example.com/auth"}}]`
+	for i := 0; i < 3; i++ {
+		if str := doc.String(); !strings.Contains(str, expected) {
+			t.Fatalf("expected\n%s\nin\n%s", expected, str)
+		}
+	}
+}
+
+func Test_externalDocs(t *testing.T) {
+	op := Operation{
+		Summary: "List pets",
+		ExternalDocs: &ExternalDocumentation{
+			Description: "More about pets",
+			Url:         mustParse("https://example.com/pets"),
+		},
+		Responses: map[string]Response{"200": {Description: "ok"}},
+	}
+
+	b, err := json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"summary":"List pets","responses":{"200":{"description":"ok"}},` +
+		`"externalDocs":{"description":"More about pets","url":"https://example.com/pets"}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+
+	op.ExternalDocs = nil
+	b, err = json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "externalDocs") {
+		t.Fatalf("expected externalDocs to be omitted: %s", b)
+	}
+}
+
+func Test_deprecatedOperation(t *testing.T) {
+	item := PathItem{Get: &Operation{Deprecated: true, Responses: map[string]Response{"200": {Description: "ok"}}}}
+	b, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"deprecated":true`) {
+		t.Fatalf("expected deprecated operation: %s", b)
+	}
+
+	item.Get.Deprecated = false
+	b, err = json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "deprecated") {
+		t.Fatalf("expected deprecated to be omitted: %s", b)
+	}
+}
+
+func Test_effectiveServers(t *testing.T) {
+	doc := NewDocument()
+	op := &Operation{}
+	item := &PathItem{Get: op}
+
+	if servers := op.EffectiveServers(doc, item); len(servers) != 1 || servers[0].Url != "/" {
+		t.Fatalf("expected default server but got %v", servers)
+	}
+
+	doc.Servers = []Server{{Url: "https://document"}}
+	if servers := op.EffectiveServers(doc, item); len(servers) != 1 || servers[0].Url != "https://document" {
+		t.Fatalf("expected document server but got %v", servers)
+	}
+
+	item.Servers = []Server{{Url: "https://path"}}
+	if servers := op.EffectiveServers(doc, item); len(servers) != 1 || servers[0].Url != "https://path" {
+		t.Fatalf("expected path server but got %v", servers)
+	}
+
+	op.Servers = []Server{{Url: "https://operation"}}
+	if servers := op.EffectiveServers(doc, item); len(servers) != 1 || servers[0].Url != "https://operation" {
+		t.Fatalf("expected operation server but got %v", servers)
+	}
+}
+
+func Test_effectiveParameters(t *testing.T) {
+	item := &PathItem{
+		Parameters: []Parameter{
+			{Name: "id", In: PathLocation, Description: "the id", Required: true},
+		},
+		Get: &Operation{
+			Parameters: []Parameter{
+				{Name: "id", In: PathLocation, Description: "the pet id", Required: true},
+				{Name: "id", In: QueryLocation, Description: "not the same"},
+			},
+		},
+		Delete: &Operation{},
+	}
+
+	params := item.Get.EffectiveParameters(item)
+	if len(params) != 2 || params[0].Description != "the pet id" || params[1].In != QueryLocation {
+		t.Fatalf("unexpected parameters: %+v", params)
+	}
+
+	params = item.Delete.EffectiveParameters(item)
+	if len(params) != 1 || params[0].Description != "the id" {
+		t.Fatalf("unexpected parameters: %+v", params)
+	}
+}
+
+func Test_pathItemSummary(t *testing.T) {
+	doc, err := FromJson([]byte(`{
+		"openapi": "3.0.1",
+		"info": {"title": "Demo API", "version": "0.0.1"},
+		"paths": {"/auth/session": {"summary": "Authentication", "description": "The Session endpoint"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, _ := doc.Paths.Get("/auth/session")
+	if item.Summary != "Authentication" || item.Description != "The Session endpoint" {
+		t.Fatalf("unexpected path item: %+v", item)
+	}
+
+	b, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"summary":"Authentication","description":"The Session endpoint"}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_links(t *testing.T) {
+	item := PathItem{
+		Post: &Operation{
+			OperationId: "createPet",
+			Responses: map[string]Response{
+				"201": {
+					Description: "created",
+					Links: map[string]Link{
+						"GetPetById": {
+							OperationId: "getPet",
+							Parameters:  map[string]interface{}{"id": "$response.body#/id"},
+							Description: "The id can be used to fetch the pet",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"post":{"operationId":"createPet","responses":{"201":{"description":"created","links":{"GetPetById":` +
+		`{"operationId":"getPet","parameters":{"id":"$response.body#/id"},"description":"The id can be used to fetch the pet"}}}}}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+}
+
+func Test_callbacks(t *testing.T) {
+	op := Operation{
+		Responses: map[string]Response{"201": {Description: "subscribed"}},
+		Callbacks: map[string]Callback{
+			"onEvent": {
+				"{$request.body#/callbackUrl}": PathItem{
+					Post: &Operation{
+						Responses: map[string]Response{"200": {Description: "received"}},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"responses":{"201":{"description":"subscribed"}},"callbacks":{"onEvent":{"{$request.body#/callbackUrl}":` +
+		`{"post":{"responses":{"200":{"description":"received"}}}}}}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+}
+
+func Test_parameterComponent(t *testing.T) {
+	ref := "#/components/parameters/limit"
+	doc := NewDocument()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1", License: &License{Name: "MIT", Url: mustParse("https://opensource.org/licenses/MIT")}}
+	doc.Components = &Components{
+		Parameters: map[string]Parameter{
+			"limit": {Name: "limit", In: QueryLocation, Description: "max items", Schema: Schema{Type: Integer}},
+		},
+	}
+	doc.Paths.Set("/pets", PathItem{
+		Get: &Operation{
+			Parameters: []Parameter{{Ref: &ref}},
+			Responses:  map[string]Response{"200": {Description: "ok"}},
+		},
+	})
+
+	str := doc.String()
+	if !strings.Contains(str, `"components":{"parameters":{"limit":{"name":"limit","in":"query","description":"max items","schema":{"type":"integer"}}}}`) {
+		t.Fatalf("expected parameter component: %s", str)
+	}
+	if !strings.Contains(str, `"parameters":[{"$ref":"#/components/parameters/limit"}]`) {
+		t.Fatalf("expected parameter reference: %s", str)
+	}
+
+	parsed, err := FromJson([]byte(str))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params := parsed.Paths.Item("/pets").Get.Parameters; len(params) != 1 || params[0].Ref == nil || *params[0].Ref != ref {
+		t.Fatalf("unexpected parameters: %+v", params)
+	}
+}
+
+func Test_urlRoundTrip(t *testing.T) {
+	contactUrl := mustParse("https://github.com/torbenschinke")
+	b, err := json.Marshal(Contact{Name: "Torben Schinke", Url: &contactUrl})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var contact Contact
+	if err := json.Unmarshal(b, &contact); err != nil {
+		t.Fatal(err)
+	}
+	if contact.Url == nil || contact.Url.String() != contactUrl.String() {
+		t.Fatalf("expected %s but got %v", contactUrl, contact.Url)
+	}
+
+	termsOfService := mustParse("https://example.com/terms?lang=en")
+	doc := NewDocument()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1", TermsOfService: &termsOfService, License: &License{Name: "MIT", Url: mustParse("https://opensource.org/licenses/MIT")}}
+	parsed, err := FromJson([]byte(doc.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Info.TermsOfService == nil || parsed.Info.TermsOfService.String() != termsOfService.String() {
+		t.Fatalf("expected %s but got %v", termsOfService, parsed.Info.TermsOfService)
+	}
+
+	var u URL
+	if err := json.Unmarshal([]byte(`"http://[::1]:namedport"`), &u); err == nil {
+		t.Fatalf("expected a malformed url error but got %s", u)
+	}
+}
+
+func Test_serverDefaultURL(t *testing.T) {
+	server := Server{
+		Url: "http://localhost:{port}/{base}",
+		Variables: map[string]ServerVariable{
+			"port": {Enum: []string{"8080", "8181"}, Default: "8080"},
+			"base": {Default: "v1"},
+		},
+	}
+
+	u, err := server.DefaultURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "http://localhost:8080/v1" {
+		t.Fatalf("unexpected url: %s", u)
+	}
+
+	server.Url = "http://localhost:{port}/{version}"
+	if _, err := server.DefaultURL(); err == nil {
+		t.Fatal("expected an error for an undeclared variable")
+	}
+
+	server.Url = "http://localhost:{port"
+	if _, err := server.DefaultURL(); err == nil {
+		t.Fatal("expected an error for an unclosed variable")
+	}
+}
+
+func Test_serverResolve(t *testing.T) {
+	server := Server{
+		Url: "{scheme}://localhost:{port}",
+		Variables: map[string]ServerVariable{
+			"scheme": {Default: "http"},
+			"port":   {Enum: []string{"8080", "8181"}, Default: "8080"},
+		},
+	}
+
+	tests := []struct {
+		vars     map[string]string
+		expected string
+		err      bool
+	}{
+		{vars: nil, expected: "http://localhost:8080"},
+		{vars: map[string]string{"port": "8181"}, expected: "http://localhost:8181"},
+		{vars: map[string]string{"scheme": "https", "port": "8181"}, expected: "https://localhost:8181"},
+		{vars: map[string]string{"port": "9090"}, err: true},
+	}
+
+	for _, tt := range tests {
+		str, err := server.Resolve(tt.vars)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("expected an error for %v but got %s", tt.vars, str)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if str != tt.expected {
+			t.Fatalf("expected %s but got %s", tt.expected, str)
+		}
+	}
+}
+
+func Test_encoding(t *testing.T) {
+	body := RequestBody{
+		Content: map[string]MediaType{
+			"multipart/form-data": {
+				Schema: Schema{
+					Type: Object,
+					Properties: map[string]Schema{
+						"metadata": {Type: Object},
+						"file":     {Type: String, Format: string(Binary)},
+					},
+				},
+				Encoding: map[string]Encoding{
+					"metadata": {ContentType: "application/json", Style: "form", Explode: true, AllowReserved: true},
+					"file": {
+						ContentType: "application/octet-stream",
+						Headers:     map[string]Header{"X-Checksum": {Description: "sha256", Schema: Schema{Type: String}}},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"content":{"multipart/form-data":{"schema":{"type":"object","properties":{"file":{"type":"string","format":"binary"},"metadata":{"type":"object"}}},` +
+		`"encoding":{"file":{"contentType":"application/octet-stream","headers":{"X-Checksum":{"description":"sha256","schema":{"type":"string"}}}},` +
+		`"metadata":{"contentType":"application/json","style":"form","explode":true,"allowReserved":true}}}}}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+}
+
+func Test_minMaxProperties(t *testing.T) {
+	one := 1
+	b, err := json.Marshal(Schema{Type: Object, MinProperties: &one})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"object","minProperties":1}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_stringIndent(t *testing.T) {
+	doc := NewDocument()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1", License: &License{Name: "MIT", Url: mustParse("https://opensource.org/licenses/MIT")}}
+	doc.Components = &Components{Schemas: map[string]Schema{}}
+	for _, name := range []string{"e", "d", "c", "b", "a"} {
+		doc.Path("/"+name).Get().Response(200, Response{Description: name}).Response(404, Response{Description: name})
+		doc.Components.Schemas[name] = Schema{Type: Object, Properties: map[string]Schema{"x": {}, "y": {}, "z": {}}}
+	}
+
+	expected := doc.StringIndent()
+	for i := 0; i < 10; i++ {
+		if str := doc.StringIndent(); str != expected {
+			t.Fatalf("expected\n%s\nbut got\n%s", expected, str)
+		}
+	}
+
+	if !strings.Contains(expected, "\n  \"paths\": {\n    \"/e\": {") {
+		t.Fatalf("expected indented paths in declaration order:\n%s", expected)
+	}
+
+	if !strings.Contains(expected, "\n    \"schemas\": {\n      \"a\": {") {
+		t.Fatalf("expected sorted and indented schemas:\n%s", expected)
+	}
+}
+
+func Test_parameterStyle(t *testing.T) {
+	query := Parameter{Name: "ids", In: QueryLocation}
+	if query.EffectiveStyle() != FormStyle || !query.EffectiveExplode() {
+		t.Fatalf("expected exploded form style for query, got %s %v", query.EffectiveStyle(), query.EffectiveExplode())
+	}
+
+	noExplode := false
+	query.Explode = &noExplode
+	if query.EffectiveExplode() {
+		t.Fatal("expected declared explode=false")
+	}
+
+	path := Parameter{Name: "id", In: PathLocation, Required: true}
+	if path.EffectiveStyle() != SimpleStyle || path.EffectiveExplode() {
+		t.Fatalf("expected non-exploded simple style for path, got %s %v", path.EffectiveStyle(), path.EffectiveExplode())
+	}
+
+	header := Parameter{Name: "X-Ids", In: HeaderLocation, Style: SimpleStyle}
+	if header.EffectiveStyle() != SimpleStyle {
+		t.Fatalf("expected simple style for header, got %s", header.EffectiveStyle())
+	}
+
+	b, err := json.Marshal(Parameter{Name: "ids", In: QueryLocation, Style: FormStyle, Explode: &noExplode, AllowEmptyValue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed Parameter
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Style != FormStyle || parsed.Explode == nil || *parsed.Explode || !parsed.AllowEmptyValue {
+		t.Fatalf("unexpected round-trip: %s", b)
+	}
+}
+
+func Test_infoContactLicense(t *testing.T) {
+	contactUrl := mustParse("https://example.com/team")
+	doc := NewDocument()
+	doc.Info = Info{
+		Title:   "api",
+		Version: "1.0",
+		Contact: &Contact{Name: "Team", Url: &contactUrl, Email: "team@example.com"},
+		License: &License{Name: "MIT", Url: mustParse("https://opensource.org/licenses/MIT")},
+	}
+
+	parsed, err := FromJson([]byte(doc.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Info.Contact.Name != "Team" || parsed.Info.Contact.Email != "team@example.com" || parsed.Info.Contact.Url.String() != "https://example.com/team" {
+		t.Fatalf("contact has been lost: %+v", parsed.Info.Contact)
+	}
+
+	if parsed.Info.License.Name != "MIT" || parsed.Info.License.Url.String() != "https://opensource.org/licenses/MIT" {
+		t.Fatalf("license has been lost: %+v", parsed.Info.License)
+	}
+
+	doc.Info = Info{Title: "api", Version: "1.0"}
+	if str := doc.String(); strings.Contains(str, "contact") || strings.Contains(str, "license") {
+		t.Fatalf("expected no contact and license: %s", str)
+	}
+}
+
+func Test_licenseIdentifier(t *testing.T) {
+	b, err := json.Marshal(License{Name: "MIT", Identifier: "MIT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"name":"MIT","identifier":"MIT"}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+}
+
+func Test_infoSummary(t *testing.T) {
+	doc := NewDocument31()
+	doc.Info = Info{Title: "api", Summary: "A pet store", Version: "1.0"}
+
+	parsed, err := FromJson([]byte(doc.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Info.Summary != "A pet store" {
+		t.Fatalf("summary has been lost: %s", doc.String())
+	}
+
+	doc.Info.Summary = ""
+	if strings.Contains(doc.String(), "summary") {
+		t.Fatalf("expected an omitted summary: %s", doc.String())
+	}
+}
+
+func Test_parameterEffectiveSchema(t *testing.T) {
+	inline := Parameter{Name: "limit", In: QueryLocation, Schema: Schema{Type: Integer}}
+	if schema, ok := inline.EffectiveSchema(); !ok || schema.Type != Integer {
+		t.Fatalf("expected the inline schema but got %v", schema)
+	}
+
+	content := Parameter{Name: "filter", In: QueryLocation, Content: JSONContent(Schema{Type: Object})}
+	if schema, ok := content.EffectiveSchema(); !ok || schema.Type != Object {
+		t.Fatalf("expected the content schema but got %v", schema)
+	}
+
+	b, err := json.Marshal(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), `"schema":{}`) {
+		t.Fatalf("expected the empty schema to be omitted: %s", b)
+	}
+
+	both := content
+	both.Schema = Schema{Type: String}
+	if _, ok := both.EffectiveSchema(); ok {
+		t.Fatal("expected an ambiguous schema")
+	}
+
+	if err := both.Validate(); err == nil {
+		t.Fatal("expected schema and content to be mutually exclusive")
+	}
+
+	if _, ok := (Parameter{Name: "empty", In: QueryLocation}).EffectiveSchema(); ok {
+		t.Fatal("expected no schema")
+	}
+
+	var untyped Parameter
+	if err := json.Unmarshal([]byte(`{"name":"any","in":"query","schema":{}}`), &untyped); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := untyped.Validate(); err != nil {
+		t.Fatalf("expected an empty schema to be valid but got %v", err)
+	}
+
+	if schema, ok := untyped.EffectiveSchema(); !ok || !reflect.DeepEqual(*schema, Schema{}) {
+		t.Fatalf("expected the empty schema but got %v", schema)
+	}
+
+	if b, err := json.Marshal(untyped); err != nil || !strings.Contains(string(b), `"schema":{}`) {
+		t.Fatalf("expected the empty schema to round-trip: %s %v", b, err)
+	}
+}
+
+func Test_elementSchema(t *testing.T) {
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{"Pet": {Type: Object, Description: "a pet"}}}
+
+	inline := Schema{Type: Array, Items: &Items{&Schema{Type: String}}}
+	if elem, ok := inline.ElementSchema(doc); !ok || elem.Type != String {
+		t.Fatalf("expected the inline items schema but got %v", elem)
+	}
+
+	pet := "#/components/schemas/Pet"
+	referenced := Schema{Type: Array, Items: &Items{&Schema{Ref: &pet}}}
+	if elem, ok := referenced.ElementSchema(doc); !ok || elem.Description != "a pet" {
+		t.Fatalf("expected the referenced items schema but got %v", elem)
+	}
+
+	unknown := "#/components/schemas/Unknown"
+	referenced.Items = &Items{&Schema{Ref: &unknown}}
+	if _, ok := referenced.ElementSchema(doc); ok {
+		t.Fatal("expected an unresolvable items schema")
+	}
+
+	if _, ok := (&Schema{Type: Object}).ElementSchema(doc); ok {
+		t.Fatal("expected no element schema for an object")
+	}
+}
+
+func Test_schemaValidateFormat(t *testing.T) {
+	valid := Schema{
+		Type: Object,
+		Properties: map[string]Schema{
+			"id":      {Type: Integer, Format: string(Int64)},
+			"created": {Types: []Type{String, Null}, Format: string(DateTime)},
+			"color":   {Type: String, Format: "hex-color"},
+		},
+	}
+
+	if errs := valid.ValidateFormat(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	invalid := Schema{Type: Array, Items: &Items{&Schema{Type: String, Format: string(Int64)}}}
+	errs := invalid.ValidateFormat()
+	if len(errs) != 1 || errs[0].Error() != "#/items: format 'int64' requires type integer but got string" {
+		t.Fatalf("expected a single violation but got %v", errs)
+	}
+}
+
+func Test_schemaTitle(t *testing.T) {
+	schema := Schema{Type: Object, Title: "Pet", Properties: map[string]Schema{"name": {Type: String}}}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), `"title":"Pet"`) {
+		t.Fatalf("expected the title to be serialized: %s", b)
+	}
+
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{"Pet": schema}}
+	parsed, err := FromJson([]byte(doc.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if title := parsed.Components.Schemas["Pet"].Title; title != "Pet" {
+		t.Fatalf("expected the title to round-trip but got '%s'", title)
+	}
+
+	if b, _ := json.Marshal(Schema{Type: String}); strings.Contains(string(b), "title") {
+		t.Fatalf("expected an empty title to be omitted: %s", b)
+	}
+}
+
+func Test_writeTo(t *testing.T) {
+	doc := validDocument()
+	doc.Info.Description = "<b>pets</b> & more"
+
+	buf := &bytes.Buffer{}
+	n, err := doc.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != int64(buf.Len()) {
+		t.Fatalf("expected %d written bytes but got %d", buf.Len(), n)
+	}
+
+	if buf.String() != doc.String()+"\n" {
+		t.Fatalf("expected\n%s\nbut got\n%s", doc.String(), buf.String())
+	}
+}
+
+func Test_fromReader(t *testing.T) {
+	doc, err := FromReader(strings.NewReader(validDocument().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Info.Title != "Demo API" || doc.Paths.Item("/pets/{id}") == nil {
+		t.Fatalf("unexpected document %s", doc.String())
+	}
+
+	_, err = FromReader(strings.NewReader(`{"openapi": 3}`))
+	var typeErr *json.UnmarshalTypeError
+	if err == nil || !strings.HasPrefix(err.Error(), "cannot decode document") || !errors.As(err, &typeErr) {
+		t.Fatalf("expected a wrapped decode error but got %v", err)
+	}
+}
+
+func Test_parameterStyleLocation(t *testing.T) {
+	p := Parameter{Name: "filter", In: QueryLocation, Style: DeepObjectStyle, Schema: Schema{Type: Object}}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.In = HeaderLocation
+	if err := p.Validate(); err == nil || err.Error() != "style 'deepObject' is not allowed for header parameters" {
+		t.Fatalf("expected an invalid style but got %v", err)
+	}
+
+	p.Style = "unknown"
+	if err := p.Validate(); err == nil {
+		t.Fatal("expected an unknown style")
+	}
+}
+
+func Test_jsonTags(t *testing.T) {
+	seen := map[reflect.Type]bool{}
+	var check func(typ reflect.Type)
+	check = func(typ reflect.Type) {
+		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+
+		if typ.Kind() != reflect.Struct || typ.PkgPath() != reflect.TypeOf(Document{}).PkgPath() || seen[typ] {
+			return
+		}
+		seen[typ] = true
+
+		names := map[string]string{}
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			check(f.Type)
+
+			name, ok := jsonFieldName(f)
+			if !ok || f.Anonymous {
+				continue
+			}
+
+			if other, ok := names[name]; ok {
+				t.Errorf("%s: the fields %s and %s have the same json name '%s'", typ.Name(), other, f.Name, name)
+			}
+			names[name] = f.Name
+		}
+	}
+
+	check(reflect.TypeOf(Document{}))
+	if len(seen) < 20 {
+		t.Fatalf("expected to check all model types but only got %d", len(seen))
+	}
+}
+
+func Test_schemaIsRequired(t *testing.T) {
+	schema := Schema{
+		Type:       Object,
+		Required:   []string{"name"},
+		Properties: map[string]Schema{"name": {Type: String}, "tag": {Type: String}},
+	}
+
+	if !schema.IsRequired("name") {
+		t.Fatal("expected name to be required")
+	}
+
+	if schema.IsRequired("tag") || schema.IsRequired("unknown") {
+		t.Fatal("expected tag to be optional")
+	}
+
+	schema.Required = nil
+	if schema.IsRequired("name") {
+		t.Fatal("expected no required properties")
+	}
+
+	array := Schema{Type: Array, Required: []string{"name"}}
+	if array.IsRequired("name") {
+		t.Fatal("expected no required properties for an array")
+	}
+}
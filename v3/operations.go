@@ -0,0 +1,109 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// methodOrder is the fixed order of http methods, like the fields of a path item are listed by the specification.
+var methodOrder = []string{"GET", "PUT", "POST", "DELETE", "PATCH"}
+
+// An OperationRef locates an operation within the document.
+type OperationRef struct {
+	Path      string     // Path is the template of the path item, e.g. /pets/{id}
+	Method    string     // Method is the upper case http method, e.g. GET
+	Operation *Operation // Operation is the declared operation, which is shared with the document
+}
+
+// Operations returns all operations of the document sorted by their path and then in the order GET, PUT, POST,
+// DELETE and PATCH.
+func (d *Document) Operations() []OperationRef {
+	var r []OperationRef
+	for _, path := range sortedKeys(d.Paths) {
+		item := d.Paths.Item(path)
+		ops := item.Map()
+		for _, method := range methodOrder {
+			if op, ok := ops[method]; ok {
+				r = append(r, OperationRef{Path: path, Method: method, Operation: op})
+			}
+		}
+	}
+	return r
+}
+
+// ResponseFor returns a copy of the response for the http status code. An exact key like 404 is preferred over a
+// range like 4XX, which in turn is preferred over the default response.
+func (o *Operation) ResponseFor(code int) (*Response, bool) {
+	for _, key := range []string{strconv.Itoa(code), fmt.Sprintf("%dXX", code/100), "default"} {
+		if resp, ok := o.Responses[key]; ok {
+			return &resp, true
+		}
+	}
+	return nil, false
+}
+
+// SuccessResponse returns a copy of the first declared 2xx response, where exact codes like 200 come before the
+// 2XX range, or the default response otherwise.
+func (o *Operation) SuccessResponse() (*Response, bool) {
+	key, ok := o.successResponseKey()
+	if !ok {
+		return nil, false
+	}
+
+	resp := o.Responses[key]
+	return &resp, true
+}
+
+// successResponseKey returns the key of the success response, see SuccessResponse.
+func (o *Operation) successResponseKey() (string, bool) {
+	for _, key := range sortedKeys(o.Responses) {
+		if strings.HasPrefix(key, "2") {
+			return key, true
+		}
+	}
+
+	if _, ok := o.Responses["default"]; ok {
+		return "default", true
+	}
+	return "", false
+}
+
+// JSONRequestSchema returns a copy of the schema of the application/json request body, where a declared content
+// type with parameters like application/json; charset=utf-8 is accepted as well. It returns false, if the
+// operation has no request body or the body cannot be json.
+func (o *Operation) JSONRequestSchema() (*Schema, bool) {
+	if o.RequestBody == nil {
+		return nil, false
+	}
+
+	for _, key := range sortedKeys(o.RequestBody.Content) {
+		if baseMediaType(key) == MediaTypeJSON {
+			schema := o.RequestBody.Content[key].Schema
+			return &schema, true
+		}
+	}
+	return nil, false
+}
+
+// RequestBodyRequired returns true, if the operation declares a request body, which is required. By
+// specification, a request body is optional, unless it is declared as required.
+func (o *Operation) RequestBodyRequired() bool {
+	return o.RequestBody != nil && o.RequestBody.Required
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_operations(t *testing.T) {
+	ok := Response{Description: "ok"}
+	doc := NewDocument()
+	doc.Path("/pets/{id}").Patch().Response(200, ok).Path().Delete().Response(204, ok).Path().Get().Response(200, ok)
+	doc.Path("/pets").Post().Response(201, ok).Path().Get().Response(200, ok)
+	doc.Path("/owners").Put().Response(200, ok)
+
+	var actual []string
+	for _, op := range doc.Operations() {
+		actual = append(actual, fmt.Sprintf("%s %s", op.Method, op.Path))
+	}
+
+	expected := []string{"PUT /owners", "GET /pets", "POST /pets", "GET /pets/{id}", "DELETE /pets/{id}", "PATCH /pets/{id}"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+
+	// the operations are shared with the document
+	doc.Operations()[0].Operation.Summary = "replace the owners"
+	if doc.Paths.Item("/owners").Put.Summary != "replace the owners" {
+		t.Fatal("expected the operation to be shared")
+	}
+}
+
+func Test_responseFor(t *testing.T) {
+	op := Operation{Responses: map[string]Response{
+		"200":     {Description: "ok"},
+		"2XX":     {Description: "success"},
+		"404":     {Description: "not found"},
+		"default": {Description: "error"},
+	}}
+
+	tests := map[int]string{200: "ok", 204: "success", 404: "not found", 500: "error"}
+	for code, description := range tests {
+		if resp, ok := op.ResponseFor(code); !ok || resp.Description != description {
+			t.Fatalf("%d: expected %s but got %+v", code, description, resp)
+		}
+	}
+
+	if resp, ok := op.SuccessResponse(); !ok || resp.Description != "ok" {
+		t.Fatalf("expected the exact success response but got %+v", resp)
+	}
+
+	delete(op.Responses, "200")
+	if resp, ok := op.SuccessResponse(); !ok || resp.Description != "success" {
+		t.Fatalf("expected the 2XX response but got %+v", resp)
+	}
+
+	delete(op.Responses, "2XX")
+	if resp, ok := op.SuccessResponse(); !ok || resp.Description != "error" {
+		t.Fatalf("expected the default response but got %+v", resp)
+	}
+
+	delete(op.Responses, "default")
+	if _, ok := op.SuccessResponse(); ok {
+		t.Fatal("expected no success response")
+	}
+
+	if _, ok := op.ResponseFor(500); ok {
+		t.Fatal("expected no response for 500")
+	}
+}
+
+func Test_jsonRequestSchema(t *testing.T) {
+	op := &Operation{RequestBody: &RequestBody{
+		Content:  map[string]MediaType{"application/json; charset=utf-8": {Schema: Schema{Type: Object, Description: "pet"}}},
+		Required: true,
+	}}
+
+	if schema, ok := op.JSONRequestSchema(); !ok || schema.Description != "pet" {
+		t.Fatalf("expected the json schema but got %+v", schema)
+	}
+
+	if !op.RequestBodyRequired() {
+		t.Fatal("expected a required body")
+	}
+
+	op.RequestBody = &RequestBody{Content: map[string]MediaType{"application/xml": {Schema: Schema{Type: Object}}}}
+	if _, ok := op.JSONRequestSchema(); ok {
+		t.Fatal("expected no json schema")
+	}
+
+	if op.RequestBodyRequired() {
+		t.Fatal("expected an optional body by default")
+	}
+
+	op.RequestBody = nil
+	if _, ok := op.JSONRequestSchema(); ok || op.RequestBodyRequired() {
+		t.Fatal("expected no body")
+	}
+}
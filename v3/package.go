@@ -15,9 +15,8 @@
  */
 
 // Package v3 of openapi contains a one-way-model of the OpenAPI, formerly known as Swagger.
-// It is used to create an instance of the specification programmatically. It is not expected that a YAML format
-// is worth the effort, because human consumers will use the Swagger UI anyway, so only the JSON format
-// is currently supported.
+// It is used to create an instance of the specification programmatically. The JSON format is the primary one,
+// however a Document can also be emitted as YAML, which is derived from its JSON representation.
 //
 // Note that each field which is annotated with *omitempty* is optional.
 package v3
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// A PathEntry is a single path template and its item.
+type PathEntry struct {
+	Path string   // Path is the template relative to the server url, e.g. /pets/{id}
+	Item PathItem // Item describes the operations of the path
+}
+
+// Paths is an ordered map of path templates to their items, which keeps the declaration order when marshalled
+// and unmarshalled, so that the logical grouping of the author is preserved and diffs of the emitted document
+// are stable. The zero value is an empty Paths ready to use. Like a map, the copies of a non-empty Paths share
+// their entries, so that a modification of one copy is visible in all of them. Use Document.Clone for an
+// independent copy.
+type Paths struct {
+	state *pathsState // state is nil for empty paths
+}
+
+// pathsState holds the entries of Paths, so that copies of Paths cannot get out of sync.
+type pathsState struct {
+	entries []PathEntry
+	index   map[string]int // index maps the path to its position in entries
+}
+
+// NewPaths returns the paths of the entries in the given order. A repeated path replaces the former item.
+func NewPaths(entries ...PathEntry) Paths {
+	var p Paths
+	for _, entry := range entries {
+		p.Set(entry.Path, entry.Item)
+	}
+	return p
+}
+
+// Len returns the amount of paths.
+func (p Paths) Len() int {
+	return len(p.all())
+}
+
+// Keys returns the path templates in declaration order.
+func (p Paths) Keys() []string {
+	keys := make([]string, 0, p.Len())
+	for _, entry := range p.all() {
+		keys = append(keys, entry.Path)
+	}
+	return keys
+}
+
+// Get returns a copy of the item of the path template.
+func (p Paths) Get(path string) (PathItem, bool) {
+	if item := p.Item(path); item != nil {
+		return *item, true
+	}
+	return PathItem{}, false
+}
+
+// Item returns the stored item of the path template or nil, so that it can be modified in place. The pointer
+// is only valid until the next call of Set or Delete.
+func (p Paths) Item(path string) *PathItem {
+	if p.state == nil {
+		return nil
+	}
+
+	if i, ok := p.state.index[path]; ok {
+		return &p.state.entries[i].Item
+	}
+	return nil
+}
+
+// Set replaces the item of an existing path template in place or appends a new one.
+func (p *Paths) Set(path string, item PathItem) {
+	if p.state == nil {
+		p.state = &pathsState{index: map[string]int{}}
+	}
+
+	s := p.state
+	if i, ok := s.index[path]; ok {
+		s.entries[i].Item = item
+		return
+	}
+
+	s.index[path] = len(s.entries)
+	s.entries = append(s.entries, PathEntry{Path: path, Item: item})
+}
+
+// Delete removes the path template and keeps the order of the remaining ones.
+func (p *Paths) Delete(path string) {
+	if p.state == nil {
+		return
+	}
+
+	s := p.state
+	i, ok := s.index[path]
+	if !ok {
+		return
+	}
+
+	s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	delete(s.index, path)
+	for j := i; j < len(s.entries); j++ {
+		s.index[s.entries[j].Path] = j
+	}
+
+	if len(s.entries) == 0 {
+		*p = Paths{}
+	}
+}
+
+// all returns the entries in declaration order.
+func (p Paths) all() []PathEntry {
+	if p.state == nil {
+		return nil
+	}
+	return p.state.entries
+}
+
+// MarshalJSON emits the paths as a json object in declaration order.
+func (p Paths) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, entry := range p.all() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(entry.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		item, err := json.Marshal(entry.Item)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(item)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON parses the json object and keeps the order of its keys.
+func (p *Paths) UnmarshalJSON(b []byte) error {
+	*p = Paths{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok == nil {
+		return nil
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("paths must be an object")
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var item PathItem
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		p.Set(tok.(string), item)
+	}
+
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_pathsOrder(t *testing.T) {
+	ok := Response{Description: "ok"}
+	doc := NewDocument()
+	doc.Path("/zoo").Get().Response(200, ok)
+	doc.Path("/animals").Get().Response(200, ok)
+	doc.Path("/keepers").Get().Response(200, ok)
+
+	expected := []string{"/zoo", "/animals", "/keepers"}
+	if !reflect.DeepEqual(doc.Paths.Keys(), expected) {
+		t.Fatalf("unexpected keys %v", doc.Paths.Keys())
+	}
+
+	b, err := json.Marshal(doc.Paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"/zoo":{"get":{"responses":{"200":{"description":"ok"}}}},"/animals":{"get":{"responses":{"200":{"description":"ok"}}}},"/keepers":{"get":{"responses":{"200":{"description":"ok"}}}}}`
+	for i := 0; i < 10; i++ {
+		b, err := json.Marshal(doc.Paths)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != want {
+			t.Fatalf("expected\n%s\nbut got\n%s", want, b)
+		}
+	}
+
+	var parsed Paths
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed.Keys(), expected) {
+		t.Fatalf("unexpected parsed keys %v", parsed.Keys())
+	}
+
+	if !reflect.DeepEqual(doc.Clone().Paths.Keys(), expected) {
+		t.Fatalf("unexpected cloned keys %v", doc.Clone().Paths.Keys())
+	}
+}
+
+func Test_pathsModify(t *testing.T) {
+	paths := NewPaths(PathEntry{Path: "/a"}, PathEntry{Path: "/b"}, PathEntry{Path: "/c"})
+
+	paths.Set("/b", PathItem{Summary: "b"})
+	if item, ok := paths.Get("/b"); !ok || item.Summary != "b" {
+		t.Fatalf("expected the replaced item but got %+v", item)
+	}
+
+	paths.Item("/c").Summary = "c"
+	paths.Delete("/a")
+	paths.Delete("/unknown")
+
+	if !reflect.DeepEqual(paths.Keys(), []string{"/b", "/c"}) || paths.Len() != 2 {
+		t.Fatalf("unexpected keys %v", paths.Keys())
+	}
+
+	if item, _ := paths.Get("/c"); item.Summary != "c" {
+		t.Fatal("expected the item to be modified in place")
+	}
+
+	if _, ok := paths.Get("/a"); ok || paths.Item("/a") != nil {
+		t.Fatal("expected /a to be deleted")
+	}
+
+	paths.Delete("/b")
+	paths.Delete("/c")
+	if !reflect.DeepEqual(paths, Paths{}) {
+		t.Fatalf("expected empty paths to be the zero value but got %+v", paths)
+	}
+}
+
+func Test_pathsCopy(t *testing.T) {
+	doc := NewDocument()
+	doc.Path("/a").Get().Response(200, Response{Description: "ok"})
+
+	paths := doc.Paths
+	paths.Set("/b", PathItem{Summary: "b"})
+	if item := doc.Paths.Item("/b"); item == nil || item.Summary != "b" {
+		t.Fatalf("expected the copy to share the added path but got %+v", item)
+	}
+
+	paths.Delete("/a")
+	if !reflect.DeepEqual(doc.Paths.Keys(), []string{"/b"}) || doc.Paths.Item("/a") != nil {
+		t.Fatalf("expected the copy to share the deletion but got %v", doc.Paths.Keys())
+	}
+
+	clone := doc.Clone()
+	clone.Paths.Set("/c", PathItem{})
+	clone.Paths.Delete("/b")
+	if !reflect.DeepEqual(doc.Paths.Keys(), []string{"/b"}) || doc.Paths.Item("/b").Summary != "b" {
+		t.Fatalf("expected the clone to be independent but got %v", doc.Paths.Keys())
+	}
+}
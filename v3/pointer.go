@@ -0,0 +1,318 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ResolvePointer walks the RFC 6901 JSON pointer, e.g. #/paths/~1auth~1session/get/responses/200, through the
+// document and returns the addressed node as it is stored in the model, e.g. a *Operation or a Response.
+// The leading # of a URI fragment is optional.
+func (d *Document) ResolvePointer(pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(d)
+	for i, token := range tokens {
+		v, err = pointerChild(v, token)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve '%s' at /%s: %w", pointer, strings.Join(tokens[:i+1], "/"), err)
+		}
+	}
+
+	if isNil(v) {
+		return nil, fmt.Errorf("cannot resolve '%s': value is nil", pointer)
+	}
+
+	return v.Interface(), nil
+}
+
+// Get returns the node, which is addressed by the RFC 6901 JSON pointer, and is a shorthand for ResolvePointer.
+func (d *Document) Get(pointer string) (interface{}, error) {
+	return d.ResolvePointer(pointer)
+}
+
+// Set replaces the node, which is addressed by the RFC 6901 JSON pointer, e.g.
+// #/paths/~1pets/get/responses/200/description, by the value. Missing intermediate nodes like nil pointers, nil
+// maps, map entries and paths are created and the token - appends to a slice. The value must be assignable to
+// the addressed node, e.g. a Response for a response, and nil resets the node to its zero value. The document
+// is only modified, if the value can be set.
+func (d *Document) Set(pointer string, value interface{}) error {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return err
+	}
+
+	if len(tokens) == 0 {
+		return fmt.Errorf("cannot replace the document")
+	}
+
+	if err := setChild(reflect.ValueOf(d).Elem(), tokens, value); err != nil {
+		return fmt.Errorf("cannot set '%s': %w", pointer, err)
+	}
+	return nil
+}
+
+// setChild sets the value at the tokens below the addressable value v. Created nodes and copies of map entries
+// are only stored into v, after the value has been set successfully.
+func setChild(v reflect.Value, tokens []string, value interface{}) error {
+	if len(tokens) == 0 {
+		return assign(v, value)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return setChild(v.Elem(), tokens, value)
+		}
+
+		ptr := reflect.New(v.Type().Elem())
+		if err := setChild(ptr.Elem(), tokens, value); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	case reflect.Interface:
+		elem := reflect.ValueOf(map[string]interface{}{})
+		if !v.IsNil() {
+			elem = v.Elem()
+		}
+
+		cpy := reflect.New(elem.Type()).Elem()
+		cpy.Set(elem)
+		if err := setChild(cpy, tokens, value); err != nil {
+			return err
+		}
+		v.Set(cpy)
+		return nil
+	}
+
+	token := tokens[0]
+	switch t := v.Addr().Interface().(type) {
+	case *Items:
+		return setChild(reflect.ValueOf(&t.Schema).Elem(), tokens, value)
+	case *AdditionalProperties:
+		return setChild(reflect.ValueOf(&t.Schema).Elem(), tokens, value)
+	case *Paths:
+		item, _ := t.Get(token)
+		if err := setChild(reflect.ValueOf(&item).Elem(), tokens[1:], value); err != nil {
+			return err
+		}
+		t.Set(token, item)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if name, ok := jsonFieldName(v.Type().Field(i)); ok && name == token {
+				return setChild(v.Field(i), tokens[1:], value)
+			}
+		}
+		return fmt.Errorf("no such field '%s' in %s", token, v.Type().Name())
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot resolve '%s' in a %s", token, v.Type())
+		}
+
+		m := v
+		if m.IsNil() {
+			m = reflect.MakeMap(v.Type())
+		}
+
+		key := reflect.ValueOf(token).Convert(v.Type().Key())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := m.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+
+		if err := setChild(elem, tokens[1:], value); err != nil {
+			return err
+		}
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+		return nil
+	case reflect.Slice:
+		if token == "-" {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setChild(elem, tokens[1:], value); err != nil {
+				return err
+			}
+			v.Set(reflect.Append(v, elem))
+			return nil
+		}
+
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 || idx >= v.Len() {
+			return fmt.Errorf("index '%s' is out of range [0,%d)", token, v.Len())
+		}
+		return setChild(v.Index(idx), tokens[1:], value)
+	default:
+		return fmt.Errorf("cannot resolve '%s' in a %s", token, v.Type())
+	}
+}
+
+// assign sets the value, which must be assignable or convertible within the same kind, e.g. a string to a
+// Location. A value is also assigned to a pointer of its type.
+func assign(v reflect.Value, value interface{}) error {
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Type().AssignableTo(v.Type()):
+		v.Set(rv)
+	case rv.Kind() == v.Kind() && rv.Type().ConvertibleTo(v.Type()):
+		v.Set(rv.Convert(v.Type()))
+	case v.Kind() == reflect.Ptr && rv.Type().AssignableTo(v.Type().Elem()):
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(rv)
+		v.Set(ptr)
+	default:
+		return fmt.Errorf("type mismatch: cannot assign %T to %s", value, v.Type())
+	}
+	return nil
+}
+
+// isNil returns true for nil pointers, interfaces, maps and slices.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// parsePointer splits the pointer into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	pointer = strings.TrimPrefix(pointer, "#")
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid json pointer '%s'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = unescapePointerToken(token)
+	}
+	return tokens, nil
+}
+
+// pointerChild returns the child of the model value, which is addressed by the json name of a field, a map key
+// or a slice index.
+func pointerChild(v reflect.Value, token string) (reflect.Value, error) {
+	v, err := modelValue(v)
+	if err != nil {
+		return v, err
+	}
+
+	if paths, ok := v.Interface().(Paths); ok {
+		item := paths.Item(token)
+		if item == nil {
+			return v, fmt.Errorf("no such path '%s'", token)
+		}
+		return reflect.ValueOf(item).Elem(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if name, ok := jsonFieldName(v.Type().Field(i)); ok && name == token {
+				return v.Field(i), nil
+			}
+		}
+		return v, fmt.Errorf("no such field '%s' in %s", token, v.Type().Name())
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			// e.g. a map[interface{}]interface{} of a free-form value, whose keys cannot be addressed by a token
+			return v, fmt.Errorf("no such key '%s'", token)
+		}
+
+		child := v.MapIndex(reflect.ValueOf(token).Convert(v.Type().Key()))
+		if !child.IsValid() {
+			return v, fmt.Errorf("no such key '%s'", token)
+		}
+		return child, nil
+	case reflect.Slice:
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 || idx >= v.Len() {
+			return v, fmt.Errorf("index '%s' is out of range [0,%d)", token, v.Len())
+		}
+		return v.Index(idx), nil
+	default:
+		return v, fmt.Errorf("cannot resolve '%s' in a %s", token, v.Type())
+	}
+}
+
+// modelValue dereferences pointers and interfaces and unwraps those model types, which are not represented
+// by their fields in json.
+func modelValue(v reflect.Value) (reflect.Value, error) {
+	for {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if v.IsNil() {
+				return v, fmt.Errorf("value is nil")
+			}
+			v = v.Elem()
+			continue
+		}
+
+		switch t := v.Interface().(type) {
+		case Items:
+			v = reflect.ValueOf(t.Schema)
+			continue
+		case AdditionalProperties:
+			if t.Schema == nil {
+				return v, fmt.Errorf("additionalProperties is not a schema")
+			}
+			v = reflect.ValueOf(t.Schema)
+			continue
+		}
+
+		return v, nil
+	}
+}
+
+// jsonFieldName returns the name of the field in json or false, if the field is not serialized.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" {
+		return "", false
+	}
+
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import "testing"
+
+func Test_resolvePointer(t *testing.T) {
+	doc := NewDocument()
+	doc.Paths.Set("/auth/session", PathItem{
+		Get: &Operation{
+			Parameters: []Parameter{{Name: "limit", In: QueryLocation}},
+			Responses:  map[string]Response{"200": {Description: "ok"}},
+		},
+	})
+
+	node, err := doc.ResolvePointer("#/paths/~1auth~1session/get/responses/200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, ok := node.(Response); !ok || resp.Description != "ok" {
+		t.Fatalf("unexpected node: %+v", node)
+	}
+
+	node, err = doc.ResolvePointer("/paths/~1auth~1session/get/parameters/0/name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node != "limit" {
+		t.Fatalf("unexpected node: %+v", node)
+	}
+
+	if _, err := doc.ResolvePointer("#/paths/~1auth~1session/get/parameters/1"); err == nil {
+		t.Fatal("expected an out of range error")
+	}
+
+	if _, err := doc.ResolvePointer("#/paths/~1auth~1session/post"); err == nil {
+		t.Fatal("expected an error for a nil operation")
+	}
+
+	if _, err := doc.ResolvePointer("#/paths/~1auth"); err == nil {
+		t.Fatal("expected an error for an unknown path")
+	}
+
+	doc.Paths.Item("/auth/session").Get.Parameters[0].Example = map[interface{}]interface{}{1: "one"}
+	if _, err := doc.ResolvePointer("#/paths/~1auth~1session/get/parameters/0/example/1"); err == nil {
+		t.Fatal("expected an error for a map without string keys")
+	}
+}
+
+func Test_setPointer(t *testing.T) {
+	doc := validDocument()
+	if err := doc.Set("#/paths/~1pets~1{id}/get/responses/200/description", "the pet"); err != nil {
+		t.Fatal(err)
+	}
+
+	node, err := doc.Get("#/paths/~1pets~1{id}/get/responses/200/description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node != "the pet" {
+		t.Fatalf("unexpected node: %+v", node)
+	}
+
+	if err := doc.Set("#/paths/~1pets/post/responses/201", Response{Description: "created"}); err != nil {
+		t.Fatal(err)
+	}
+	if op := doc.Paths.Item("/pets").Post; op == nil || op.Responses["201"].Description != "created" {
+		t.Fatalf("expected the intermediate path, operation and responses to be created but got %+v", op)
+	}
+
+	if err := doc.Set("#/components/schemas/Pet/properties/tags/items/type", String); err != nil {
+		t.Fatal(err)
+	}
+	if items := doc.Components.Schemas["Pet"].Properties["tags"].Items; items == nil || items.Type != String {
+		t.Fatalf("expected the intermediate schemas to be created but got %+v", doc.Components)
+	}
+
+	if err := doc.Set("#/paths/~1pets~1{id}/get/parameters/-", Parameter{Name: "verbose", In: QueryLocation}); err != nil {
+		t.Fatal(err)
+	}
+	if params := doc.Paths.Item("/pets/{id}").Get.Parameters; len(params) != 1 || params[0].Name != "verbose" {
+		t.Fatalf("expected an appended parameter but got %+v", params)
+	}
+
+	if err := doc.Set("#/paths/~1pets~1{id}/get/responses/200/description", 42); err == nil {
+		t.Fatal("expected a type mismatch")
+	}
+
+	if err := doc.Set("#/webhooks/newPet/post/unknown", "x"); err == nil {
+		t.Fatal("expected an unknown field")
+	}
+	if doc.Webhooks != nil {
+		t.Fatalf("expected no partial modification but got %+v", doc.Webhooks)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import "reflect"
+
+// A ProjectionMode selects the view of a schema, see Schema.Project.
+type ProjectionMode int
+
+const (
+	RequestProjection  ProjectionMode = iota // RequestProjection removes the readOnly properties
+	ResponseProjection                       // ResponseProjection removes the writeOnly properties
+)
+
+// Project returns a deep copy of the schema for either a request or a response body. Properties, which are
+// readOnly, are removed from requests and properties, which are writeOnly, from responses, including nested
+// objects, items and compositions, and are removed from the required lists as well. If doc is not nil, references
+// are expanded first, so that the properties of referenced components are projected as well. Recursive references
+// are kept. An error is returned, if a reference cannot be resolved.
+func (s *Schema) Project(doc *Document, mode ProjectionMode) (*Schema, error) {
+	c := &copier{doc: doc, convert: func(s *Schema) {
+		var removed []string
+		for _, name := range sortedKeys(s.Properties) {
+			prop := s.Properties[name]
+			if (mode == RequestProjection && prop.ReadOnly) || (mode == ResponseProjection && prop.WriteOnly) {
+				delete(s.Properties, name)
+				removed = append(removed, name)
+			}
+		}
+
+		if len(removed) == 0 {
+			return
+		}
+
+		var required []string
+		for _, name := range s.Required {
+			if !containsString(removed, name) {
+				required = append(required, name)
+			}
+		}
+		s.Required = required
+	}}
+
+	v, err := c.copy(reflect.ValueOf(s))
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface().(*Schema), nil
+}
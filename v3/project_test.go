@@ -0,0 +1,116 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func projectedSchema() Schema {
+	return Schema{
+		Type:     Object,
+		Required: []string{"id", "name", "password"},
+		Properties: map[string]Schema{
+			"id":       {Type: Integer, ReadOnly: true},
+			"name":     {Type: String},
+			"password": {Type: String, WriteOnly: true},
+			"owner": {
+				Type:       Object,
+				Required:   []string{"id"},
+				Properties: map[string]Schema{"id": {Type: Integer, ReadOnly: true}, "name": {Type: String}},
+			},
+		},
+	}
+}
+
+func Test_projectRequest(t *testing.T) {
+	schema := projectedSchema()
+	request, err := schema.Project(nil, RequestProjection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := request.Properties["id"]; ok {
+		t.Fatalf("expected the read only id to be removed but got %s", toJson(t, request))
+	}
+
+	if !reflect.DeepEqual(request.Required, []string{"name", "password"}) {
+		t.Fatalf("unexpected required properties %v", request.Required)
+	}
+
+	owner := request.Properties["owner"]
+	if _, ok := owner.Properties["id"]; ok || len(owner.Required) != 0 {
+		t.Fatalf("expected the nested id to be removed but got %s", toJson(t, owner))
+	}
+
+	if !reflect.DeepEqual(schema, projectedSchema()) {
+		t.Fatal("expected the schema not to be modified")
+	}
+}
+
+func Test_projectResponse(t *testing.T) {
+	schema := projectedSchema()
+	response, err := schema.Project(nil, ResponseProjection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := response.Properties["password"]; ok {
+		t.Fatalf("expected the write only password to be removed but got %s", toJson(t, response))
+	}
+
+	if !reflect.DeepEqual(response.Required, []string{"id", "name"}) {
+		t.Fatalf("unexpected required properties %v", response.Required)
+	}
+}
+
+func Test_projectReference(t *testing.T) {
+	owner := "#/components/schemas/Owner"
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Owner": {
+			Type:       Object,
+			Required:   []string{"id", "name"},
+			Properties: map[string]Schema{"id": {Type: Integer, ReadOnly: true}, "name": {Type: String}},
+		},
+	}}
+
+	schema := Schema{Type: Object, Properties: map[string]Schema{"owner": {Ref: &owner}}}
+	request, err := schema.Project(doc, RequestProjection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projected := request.Properties["owner"]
+	if _, ok := projected.Properties["id"]; ok || projected.Ref != nil {
+		t.Fatalf("expected the referenced read only id to be removed but got %s", toJson(t, projected))
+	}
+
+	if !reflect.DeepEqual(projected.Required, []string{"name"}) {
+		t.Fatalf("unexpected required properties %v", projected.Required)
+	}
+
+	if _, ok := doc.Components.Schemas["Owner"].Properties["id"]; !ok {
+		t.Fatal("expected the component not to be modified")
+	}
+
+	unknown := "#/components/schemas/Unknown"
+	if _, err := (&Schema{Ref: &unknown}).Project(doc, RequestProjection); err == nil {
+		t.Fatal("expected an error for an unresolvable reference")
+	}
+}
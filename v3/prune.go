@@ -0,0 +1,136 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"strings"
+)
+
+// PruneUnusedComponents removes all components, which are not reachable from the paths by following the
+// references transitively, e.g. a schema A which is only referenced by an unused schema B is removed as well.
+// It returns the amount of removed components. Security schemes are kept, if their
+// name is used by a security requirement of the document or an operation. If no component remains, the
+// components are set to nil.
+func (d *Document) PruneUnusedComponents() int {
+	if d.Components == nil {
+		return 0
+	}
+
+	used := map[string]bool{}
+	var pending []string
+	visit := func(path []string, node interface{}) error {
+		for _, ref := range referencesOf(node) {
+			if !used[ref] {
+				used[ref] = true
+				pending = append(pending, ref)
+			}
+		}
+
+		var security []SecurityRequirement
+		switch n := node.(type) {
+		case Document:
+			security = n.Security
+		case Operation:
+			security = n.Security
+		}
+
+		for _, requirement := range security {
+			for name := range requirement {
+				used[pointerOf("components", "securitySchemes", name)] = true
+			}
+		}
+		return nil
+	}
+
+	_ = d.Walk(func(path []string, node interface{}) error {
+		if len(path) > 0 && path[0] == "components" {
+			return nil
+		}
+		return visit(path, node)
+	})
+
+	components := reflect.ValueOf(d.Components).Elem()
+	for len(pending) > 0 {
+		ref := pending[0]
+		pending = pending[1:]
+		if component, ok := componentValue(components, ref); ok {
+			_ = walkValue(component, nil, visit)
+		}
+	}
+
+	removed := 0
+	empty := true
+	for i := 0; i < components.NumField(); i++ {
+		kind, ok := jsonFieldName(components.Type().Field(i))
+		field := components.Field(i)
+		if !ok || field.Kind() != reflect.Map {
+			continue
+		}
+
+		for _, key := range field.MapKeys() {
+			if !used[pointerOf("components", kind, key.String())] {
+				field.SetMapIndex(key, reflect.Value{})
+				removed++
+			}
+		}
+		empty = empty && field.Len() == 0
+	}
+
+	if empty {
+		// otherwise an empty components object would be emitted
+		d.Components = nil
+	}
+
+	return removed
+}
+
+// referencesOf returns the $ref of the model struct and the schemas of a discriminator mapping.
+func referencesOf(node interface{}) []string {
+	var refs []string
+	if ref := refOf(reflect.ValueOf(node)); ref != "" {
+		refs = append(refs, ref)
+	}
+
+	if discriminator, ok := node.(Discriminator); ok {
+		for _, value := range discriminator.Mapping {
+			if !strings.HasPrefix(value, "#") {
+				// a plain name implies a schema of the components
+				value = pointerOf("components", "schemas", value)
+			}
+			refs = append(refs, value)
+		}
+	}
+
+	return refs
+}
+
+// componentValue returns the value of a local reference like #/components/schemas/Pet.
+func componentValue(components reflect.Value, ref string) (reflect.Value, bool) {
+	tokens, err := parsePointer(ref)
+	if err != nil || len(tokens) != 3 || tokens[0] != "components" {
+		return reflect.Value{}, false
+	}
+
+	field, ok := jsonField(components.Type(), tokens[1])
+	if !ok || field.Type.Kind() != reflect.Map {
+		return reflect.Value{}, false
+	}
+
+	value := components.FieldByIndex(field.Index).MapIndex(reflect.ValueOf(tokens[2]))
+	return value, value.IsValid()
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_pruneUnusedComponents(t *testing.T) {
+	pet := "#/components/schemas/Pet"
+	category := "#/components/schemas/Category"
+	limit := "#/components/parameters/limit"
+	orphan := "#/components/schemas/Orphan"
+
+	doc := NewDocument()
+	doc.Security = []SecurityRequirement{{"apiKey": nil}}
+	doc.Path("/pets").Get().
+		Parameter(Parameter{Ref: &limit}).
+		Response(200, Response{Description: "ok", Content: JSONContent(Schema{Type: Array, Items: &Items{&Schema{Ref: &pet}}})})
+	doc.Components = &Components{
+		Schemas: map[string]Schema{
+			"Pet":      {Type: Object, Properties: map[string]Schema{"category": {Ref: &category}}},
+			"Category": {Type: Object},
+			"Orphan":   {Type: Object, Properties: map[string]Schema{"pet": {Ref: &pet}}},
+			"Child":    {Type: Object, Properties: map[string]Schema{"parent": {Ref: &orphan}}},
+		},
+		Parameters: map[string]Parameter{
+			"limit":  {Name: "limit", In: QueryLocation, Schema: Schema{Type: Integer}},
+			"offset": {Name: "offset", In: QueryLocation, Schema: Schema{Type: Integer}},
+		},
+		SecuritySchemes: map[string]SecurityScheme{
+			"apiKey": {Type: APIKeySecurity, Name: "X-Api-Key", In: HeaderLocation},
+			"oauth":  {Type: OAuth2Security},
+		},
+	}
+
+	if removed := doc.PruneUnusedComponents(); removed != 4 {
+		t.Fatalf("expected 4 removed components but got %d", removed)
+	}
+
+	if !reflect.DeepEqual(sortedKeys(doc.Components.Schemas), []string{"Category", "Pet"}) {
+		t.Fatalf("unexpected schemas %v", sortedKeys(doc.Components.Schemas))
+	}
+
+	if !reflect.DeepEqual(sortedKeys(doc.Components.Parameters), []string{"limit"}) {
+		t.Fatalf("unexpected parameters %v", sortedKeys(doc.Components.Parameters))
+	}
+
+	if !reflect.DeepEqual(sortedKeys(doc.Components.SecuritySchemes), []string{"apiKey"}) {
+		t.Fatalf("unexpected security schemes %v", sortedKeys(doc.Components.SecuritySchemes))
+	}
+
+	if removed := doc.PruneUnusedComponents(); removed != 0 {
+		t.Fatalf("expected nothing to prune but got %d", removed)
+	}
+}
+
+func Test_pruneAllComponents(t *testing.T) {
+	doc := NewDocument()
+	doc.Path("/pets").Get().Response(200, Response{Description: "ok"})
+	doc.Components = &Components{Schemas: map[string]Schema{"Orphan": {Type: Object}}}
+
+	if removed := doc.PruneUnusedComponents(); removed != 1 || doc.Components != nil {
+		t.Fatalf("expected the components to be removed but got %d %+v", removed, doc.Components)
+	}
+
+	if strings.Contains(doc.String(), "components") {
+		t.Fatalf("expected no components in %s", doc)
+	}
+}
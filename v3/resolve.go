@@ -0,0 +1,172 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"fmt"
+	"strings"
+)
+
+const componentsPrefix = "#/components/"
+
+// A RefReason tells, why a reference cannot be resolved.
+type RefReason string
+
+const (
+	UnsupportedRefPrefix RefReason = "unsupported prefix"  // UnsupportedRefPrefix is not a local schema reference
+	NilComponents        RefReason = "no components"       // NilComponents means that the document declares no schemas
+	MissingComponent     RefReason = "undefined component" // MissingComponent means that the name is not declared
+)
+
+// A RefError is returned for a reference, which cannot be resolved.
+type RefError struct {
+	Ref    string    // Ref is the offending reference
+	Reason RefReason // Reason tells, why the reference cannot be resolved
+}
+
+func (e *RefError) Error() string {
+	return fmt.Sprintf("cannot resolve '%s': %s", e.Ref, e.Reason)
+}
+
+// ResolveSchemaRef resolves a local schema reference like #/components/schemas/Pet and returns the name and a
+// pointer to a copy of the schema. Other than ResolveRef, it returns a *RefError, which tells why the reference
+// cannot be resolved.
+func (d *Document) ResolveSchemaRef(ref string) (string, *Schema, error) {
+	if !strings.HasPrefix(ref, schemasPrefix) {
+		return "", nil, &RefError{Ref: ref, Reason: UnsupportedRefPrefix}
+	}
+
+	if d.Components == nil || d.Components.Schemas == nil {
+		return "", nil, &RefError{Ref: ref, Reason: NilComponents}
+	}
+
+	name := unescapePointerToken(ref[len(schemasPrefix):])
+	schema, ok := d.Components.Schemas[name]
+	if !ok {
+		return "", nil, &RefError{Ref: ref, Reason: MissingComponent}
+	}
+	return name, &schema, nil
+}
+
+// ResolveComponent resolves a local reference like #/components/parameters/MyParameter and returns the name and
+// a pointer to a copy of the component, e.g. a *Parameter. An error is returned for references which are not
+// local component references, for unknown component types and for dangling names.
+func (d *Document) ResolveComponent(ref string) (string, interface{}, error) {
+	if !strings.HasPrefix(ref, componentsPrefix) {
+		return "", nil, fmt.Errorf("'%s' is not a local component reference", ref)
+	}
+
+	segments := strings.SplitN(ref[len(componentsPrefix):], "/", 2)
+	if len(segments) != 2 || segments[1] == "" {
+		return "", nil, fmt.Errorf("'%s' does not name a component", ref)
+	}
+
+	kind, name := segments[0], unescapePointerToken(segments[1])
+	var c Components
+	if d.Components != nil {
+		c = *d.Components
+	}
+
+	var component interface{}
+	switch kind {
+	case "schemas":
+		if v, ok := c.Schemas[name]; ok {
+			component = &v
+		}
+	case "responses":
+		if v, ok := c.Responses[name]; ok {
+			component = &v
+		}
+	case "parameters":
+		if v, ok := c.Parameters[name]; ok {
+			component = &v
+		}
+	case "examples":
+		if v, ok := c.Examples[name]; ok {
+			component = &v
+		}
+	case "requestBodies":
+		if v, ok := c.RequestBodies[name]; ok {
+			component = &v
+		}
+	case "headers":
+		if v, ok := c.Headers[name]; ok {
+			component = &v
+		}
+	case "securitySchemes":
+		if v, ok := c.SecuritySchemes[name]; ok {
+			component = &v
+		}
+	case "links":
+		if v, ok := c.Links[name]; ok {
+			component = &v
+		}
+	case "callbacks":
+		if v, ok := c.Callbacks[name]; ok {
+			component = &v
+		}
+	default:
+		return "", nil, fmt.Errorf("'%s' refers to the unknown component type '%s'", ref, kind)
+	}
+
+	if component == nil {
+		return "", nil, fmt.Errorf("'%s' refers to the undefined %s component '%s'", ref, kind, name)
+	}
+
+	return name, component, nil
+}
+
+// ResolveParameterRef tries to resolve the referenced parameter from the components.
+func (d *Document) ResolveParameterRef(ref string) (string, *Parameter) {
+	name, c, err := d.ResolveComponent(ref)
+	if p, ok := c.(*Parameter); err == nil && ok {
+		return name, p
+	}
+	return "", nil
+}
+
+// ResolveResponseRef tries to resolve the referenced response from the components.
+func (d *Document) ResolveResponseRef(ref string) (string, *Response) {
+	name, c, err := d.ResolveComponent(ref)
+	if r, ok := c.(*Response); err == nil && ok {
+		return name, r
+	}
+	return "", nil
+}
+
+// ResolveRequestBodyRef tries to resolve the referenced request body from the components.
+func (d *Document) ResolveRequestBodyRef(ref string) (string, *RequestBody) {
+	name, c, err := d.ResolveComponent(ref)
+	if r, ok := c.(*RequestBody); err == nil && ok {
+		return name, r
+	}
+	return "", nil
+}
+
+// ResolveHeaderRef tries to resolve the referenced header from the components.
+func (d *Document) ResolveHeaderRef(ref string) (string, *Header) {
+	name, c, err := d.ResolveComponent(ref)
+	if h, ok := c.(*Header); err == nil && ok {
+		return name, h
+	}
+	return "", nil
+}
+
+// unescapePointerToken decodes ~1 to / and ~0 to ~, in this order, see RFC 6901.
+func unescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
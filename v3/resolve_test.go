@@ -0,0 +1,88 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_resolveComponent(t *testing.T) {
+	doc := NewDocument()
+	doc.Components = &Components{
+		Parameters: map[string]Parameter{"limit": {Name: "limit", In: QueryLocation}},
+		Responses:  map[string]Response{"NotFound": {Description: "not found"}},
+	}
+
+	name, param := doc.ResolveParameterRef("#/components/parameters/limit")
+	if name != "limit" || param == nil || param.In != QueryLocation {
+		t.Fatalf("unexpected parameter %s: %+v", name, param)
+	}
+
+	if name, resp := doc.ResolveResponseRef("#/components/responses/NotFound"); name != "NotFound" || resp.Description != "not found" {
+		t.Fatalf("unexpected response %s: %+v", name, resp)
+	}
+
+	if _, param := doc.ResolveParameterRef("#/components/responses/NotFound"); param != nil {
+		t.Fatalf("expected type mismatch but got %+v", param)
+	}
+
+	for _, ref := range []string{
+		"#/components/parameters/offset",
+		"#/components/unknown/limit",
+		"#/components/parameters",
+		"Pet.json#/Pet",
+	} {
+		if _, c, err := doc.ResolveComponent(ref); err == nil {
+			t.Fatalf("expected an error for %s but got %+v", ref, c)
+		}
+	}
+}
+
+func Test_resolveSchemaRef(t *testing.T) {
+	doc := NewDocument()
+	if _, _, err := doc.ResolveSchemaRef("#/components/schemas/Pet"); !isRefError(err, NilComponents) {
+		t.Fatalf("expected missing components but got %v", err)
+	}
+
+	doc.Components = &Components{Schemas: map[string]Schema{"Pet": {Type: Object}}}
+	if name, schema, err := doc.ResolveSchemaRef("#/components/schemas/Pet"); err != nil || name != "Pet" || schema.Type != Object {
+		t.Fatalf("expected the pet schema but got %s %v %v", name, schema, err)
+	}
+
+	_, _, err := doc.ResolveSchemaRef("#/components/schemas/Owner")
+	if !isRefError(err, MissingComponent) {
+		t.Fatalf("expected a missing component but got %v", err)
+	}
+
+	if err.Error() != "cannot resolve '#/components/schemas/Owner': undefined component" {
+		t.Fatalf("unexpected message %v", err)
+	}
+
+	if _, _, err := doc.ResolveSchemaRef("pet.json#/Pet"); !isRefError(err, UnsupportedRefPrefix) {
+		t.Fatalf("expected an unsupported prefix but got %v", err)
+	}
+
+	if name, schema := doc.ResolveRef("#/components/schemas/Owner"); name != "" || schema != nil {
+		t.Fatalf("expected no schema but got %s %v", name, schema)
+	}
+}
+
+func isRefError(err error, reason RefReason) bool {
+	var refErr *RefError
+	return errors.As(err, &refErr) && refErr.Reason == reason
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SerializeValue renders the value of a query parameter according to its effective style and explode flag,
+// e.g. an exploded form array as ids=1&ids=2, a non-exploded one as ids=1,2 and a deepObject as
+// filter[name]=x&filter[age]=2. The value is converted into its json representation first, so that structs are
+// serialized like objects. Object properties are emitted in sorted order. A nil value results in no values.
+func (p Parameter) SerializeValue(v interface{}) (url.Values, error) {
+	if p.In != QueryLocation {
+		return nil, fmt.Errorf("cannot serialize a %s parameter as url values", p.In)
+	}
+
+	value, err := serializableJson(v)
+	if err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	if value == nil {
+		return values, nil
+	}
+
+	style := p.EffectiveStyle()
+	switch t := value.(type) {
+	case map[string]interface{}:
+		switch {
+		case style == DeepObjectStyle:
+			return values, serializeDeepObject(values, p.Name, t)
+		case style == FormStyle && p.EffectiveExplode():
+			for _, key := range sortedKeys(t) {
+				str, err := serializeScalar(t[key])
+				if err != nil {
+					return nil, err
+				}
+				values.Add(key, str)
+			}
+			return values, nil
+		}
+
+		tokens, err := serializeProperties(t, false)
+		if err != nil {
+			return nil, err
+		}
+		values.Set(p.Name, strings.Join(tokens, styleDelimiter(style)))
+		return values, nil
+	case []interface{}:
+		if style == DeepObjectStyle {
+			return nil, fmt.Errorf("style %s requires an object", style)
+		}
+
+		tokens, err := serializeScalars(t)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.EffectiveExplode() {
+			values[p.Name] = tokens
+		} else {
+			values.Set(p.Name, strings.Join(tokens, styleDelimiter(style)))
+		}
+		return values, nil
+	default:
+		if style == DeepObjectStyle {
+			return nil, fmt.Errorf("style %s requires an object", style)
+		}
+
+		str, err := serializeScalar(t)
+		if err != nil {
+			return nil, err
+		}
+		values.Set(p.Name, str)
+		return values, nil
+	}
+}
+
+// SerializeHeader renders the value of a header parameter in the simple style, e.g. an array as 1,2 and an object
+// as role,admin,name,Rex or, if exploded, as role=admin,name=Rex. The value is converted into its json
+// representation first and object properties are emitted in sorted order. A nil value results in the empty string.
+func (p Parameter) SerializeHeader(v interface{}) (string, error) {
+	if p.In != HeaderLocation {
+		return "", fmt.Errorf("cannot serialize a %s parameter as header", p.In)
+	}
+
+	value, err := serializableJson(v)
+	if err != nil {
+		return "", err
+	}
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+		tokens, err := serializeProperties(t, p.EffectiveExplode())
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(tokens, ","), nil
+	case []interface{}:
+		tokens, err := serializeScalars(t)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(tokens, ","), nil
+	default:
+		return serializeScalar(t)
+	}
+}
+
+// SerializeCookie renders the value of a cookie parameter in the form style, where a non-exploded array is
+// joined by commas like 1,2 and an object is emitted as role,admin,name,Rex. Exploded arrays and objects would
+// repeat the cookie and are rejected, so that the explode flag must be false for them, although it defaults to true
+// for the form style. The value is converted into its json representation first.
+func (p Parameter) SerializeCookie(v interface{}) (*http.Cookie, error) {
+	if p.In != CookieLocation {
+		return nil, fmt.Errorf("cannot serialize a %s parameter as cookie", p.In)
+	}
+
+	value, err := serializableJson(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var str string
+	switch t := value.(type) {
+	case map[string]interface{}, []interface{}:
+		if p.EffectiveExplode() {
+			return nil, fmt.Errorf("cannot serialize an exploded %T as a single cookie", v)
+		}
+
+		var tokens []string
+		if obj, ok := t.(map[string]interface{}); ok {
+			tokens, err = serializeProperties(obj, false)
+		} else {
+			tokens, err = serializeScalars(t.([]interface{}))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		str = strings.Join(tokens, ",")
+	default:
+		if str, err = serializeScalar(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return &http.Cookie{Name: p.Name, Value: str}, nil
+}
+
+// serializeProperties returns the sorted properties as key=value tokens, if exploded, and otherwise as
+// alternating key and value tokens.
+func serializeProperties(obj map[string]interface{}, explode bool) ([]string, error) {
+	var tokens []string
+	for _, key := range sortedKeys(obj) {
+		str, err := serializeScalar(obj[key])
+		if err != nil {
+			return nil, err
+		}
+
+		if explode {
+			tokens = append(tokens, key+"="+str)
+		} else {
+			tokens = append(tokens, key, str)
+		}
+	}
+	return tokens, nil
+}
+
+// serializeScalars formats each primitive item.
+func serializeScalars(items []interface{}) ([]string, error) {
+	var tokens []string
+	for _, item := range items {
+		str, err := serializeScalar(item)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, str)
+	}
+	return tokens, nil
+}
+
+// serializeDeepObject adds each property as name[key], where nested objects continue the brackets, e.g.
+// filter[owner][name], and arrays repeat the key.
+func serializeDeepObject(values url.Values, name string, obj map[string]interface{}) error {
+	for _, key := range sortedKeys(obj) {
+		nested := name + "[" + key + "]"
+		switch t := obj[key].(type) {
+		case map[string]interface{}:
+			if err := serializeDeepObject(values, nested, t); err != nil {
+				return err
+			}
+		case []interface{}:
+			for _, item := range t {
+				str, err := serializeScalar(item)
+				if err != nil {
+					return err
+				}
+				values.Add(nested, str)
+			}
+		default:
+			str, err := serializeScalar(t)
+			if err != nil {
+				return err
+			}
+			values.Add(nested, str)
+		}
+	}
+	return nil
+}
+
+// styleDelimiter returns the delimiter of non-exploded values.
+func styleDelimiter(style string) string {
+	switch style {
+	case SpaceDelimitedStyle:
+		return " "
+	case PipeDelimitedStyle:
+		return "|"
+	default:
+		return ","
+	}
+}
+
+// serializableJson converts a go value into its generic json representation like normalizeJson, but keeps the
+// numbers as json.Number, so that large integers like int64 ids are not rounded to a float64.
+func serializableJson(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var r interface{}
+	err = dec.Decode(&r)
+	return r, err
+}
+
+// serializeScalar formats a primitive json value, where null becomes the empty string.
+func serializeScalar(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("cannot serialize a nested %T", v)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func Test_serializeDeepObject(t *testing.T) {
+	p := Parameter{Name: "filter", In: QueryLocation, Style: DeepObjectStyle, Schema: Schema{Type: Object}}
+	values, err := p.SerializeValue(map[string]interface{}{
+		"name":  "Rex",
+		"age":   2,
+		"owner": map[string]interface{}{"city": "Berlin"},
+		"tags":  []string{"a", "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := url.Values{
+		"filter[name]":        {"Rex"},
+		"filter[age]":         {"2"},
+		"filter[owner][city]": {"Berlin"},
+		"filter[tags]":        {"a", "b"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("expected %v but got %v", expected, values)
+	}
+
+	if values.Encode() != "filter%5Bage%5D=2&filter%5Bname%5D=Rex&filter%5Bowner%5D%5Bcity%5D=Berlin&filter%5Btags%5D=a&filter%5Btags%5D=b" {
+		t.Fatalf("unexpected query %s", values.Encode())
+	}
+
+	if _, err := p.SerializeValue([]int{1, 2}); err == nil {
+		t.Fatal("expected an error for an array")
+	}
+}
+
+func Test_serializeForm(t *testing.T) {
+	ids := Parameter{Name: "ids", In: QueryLocation, Schema: Schema{Type: Array}}
+	values, err := ids.SerializeValue([]int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Encode() != "ids=1&ids=2" {
+		t.Fatalf("unexpected exploded query %s", values.Encode())
+	}
+
+	explode := false
+	ids.Explode = &explode
+	values, err = ids.SerializeValue([]int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("ids") != "1,2" {
+		t.Fatalf("unexpected query %s", values.Encode())
+	}
+
+	ids.Style = PipeDelimitedStyle
+	if values, _ := ids.SerializeValue([]int{1, 2}); values.Get("ids") != "1|2" {
+		t.Fatalf("unexpected pipe delimited query %s", values.Encode())
+	}
+
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	pos := Parameter{Name: "pos", In: QueryLocation, Schema: Schema{Type: Object}}
+	if values, _ := pos.SerializeValue(point{X: 1, Y: 2}); values.Encode() != "x=1&y=2" {
+		t.Fatalf("unexpected exploded object %s", values.Encode())
+	}
+
+	pos.Explode = &explode
+	if values, _ := pos.SerializeValue(point{X: 1, Y: 2}); values.Get("pos") != "x,1,y,2" {
+		t.Fatalf("unexpected object %s", values.Encode())
+	}
+
+	// above 2^53 an int64 cannot be represented by a float64
+	id := Parameter{Name: "id", In: QueryLocation, Schema: Schema{Type: Integer, Format: "int64"}}
+	if values, err := id.SerializeValue(int64(9007199254740993)); err != nil || values.Get("id") != "9007199254740993" {
+		t.Fatalf("unexpected large integer %s %v", values.Encode(), err)
+	}
+
+	if values, _ := id.SerializeValue(1.5); values.Get("id") != "1.5" {
+		t.Fatalf("unexpected number %s", values.Encode())
+	}
+}
+
+func Test_serializeHeader(t *testing.T) {
+	p := Parameter{Name: "X-Ids", In: HeaderLocation, Schema: Schema{Type: Array}}
+	if str, err := p.SerializeHeader([]int{1, 2, 3}); err != nil || str != "1,2,3" {
+		t.Fatalf("unexpected header '%s' %v", str, err)
+	}
+
+	explode := true
+	p.Explode = &explode
+	if str, err := p.SerializeHeader([]int{1, 2, 3}); err != nil || str != "1,2,3" {
+		t.Fatalf("unexpected exploded header '%s' %v", str, err)
+	}
+
+	obj := map[string]interface{}{"role": "admin", "id": 5}
+	if str, _ := p.SerializeHeader(obj); str != "id=5,role=admin" {
+		t.Fatalf("unexpected exploded object '%s'", str)
+	}
+
+	p.Explode = nil
+	if str, _ := p.SerializeHeader(obj); str != "id,5,role,admin" {
+		t.Fatalf("unexpected object '%s'", str)
+	}
+
+	if str, _ := p.SerializeHeader(true); str != "true" {
+		t.Fatalf("unexpected scalar '%s'", str)
+	}
+
+	ids := []int64{9007199254740993, -9007199254740995}
+	if str, err := p.SerializeHeader(ids); err != nil || str != "9007199254740993,-9007199254740995" {
+		t.Fatalf("unexpected large integers '%s' %v", str, err)
+	}
+}
+
+func Test_serializeCookie(t *testing.T) {
+	session := Parameter{Name: "session", In: CookieLocation, Schema: Schema{Type: String}}
+	cookie, err := session.SerializeCookie("abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cookie.String() != "session=abc123" {
+		t.Fatalf("unexpected cookie %s", cookie)
+	}
+
+	explode := false
+	ids := Parameter{Name: "ids", In: CookieLocation, Explode: &explode, Schema: Schema{Type: Array}}
+	cookie, err = ids.SerializeCookie([]int{3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cookie.Name != "ids" || cookie.Value != "3,4,5" {
+		t.Fatalf("unexpected cookie %s", cookie)
+	}
+
+	id := Parameter{Name: "id", In: CookieLocation, Schema: Schema{Type: Integer, Format: "int64"}}
+	if cookie, err := id.SerializeCookie(int64(9007199254740993)); err != nil || cookie.Value != "9007199254740993" {
+		t.Fatalf("unexpected large integer cookie %v %v", cookie, err)
+	}
+
+	ids.Explode = nil
+	if _, err := ids.SerializeCookie([]int{3, 4, 5}); err == nil {
+		t.Fatal("expected an error for an exploded array")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FromJsonStrict parses the document like FromJson but rejects any field which is not declared by the
+// specification, e.g. a misspelled respones, and reports the json pointer of the offending field. Specification
+// extensions starting with x- are allowed everywhere. Because the decoder option DisallowUnknownFields is not
+// passed into the custom unmarshalers of the model, the generic json tree is checked against the model types first.
+func FromJsonStrict(data []byte) (*Document, error) {
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	if err := checkKnownFields(reflect.TypeOf(Document{}), tree, nil); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	doc := &Document{}
+	if err := dec.Decode(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// checkKnownFields returns an error for the first json object key, which is neither declared by the model type
+// nor an extension.
+func checkKnownFields(t reflect.Type, v interface{}, path []string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeOf(URL{}):
+		return nil
+	case reflect.TypeOf(Items{}):
+		t = reflect.TypeOf(Schema{})
+	case reflect.TypeOf(Paths{}):
+		t = reflect.TypeOf(map[string]PathItem{})
+	case reflect.TypeOf(AdditionalProperties{}):
+		if _, ok := v.(bool); ok {
+			return nil
+		}
+		t = reflect.TypeOf(Schema{})
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		for _, key := range sortedKeys(obj) {
+			if strings.HasPrefix(key, "x-") {
+				continue
+			}
+
+			field, ok := jsonField(t, key)
+			if !ok {
+				return fmt.Errorf("unknown field '%s' at %s", key, pointerOf(appendPath(path, key)...))
+			}
+
+			if err := checkKnownFields(field.Type, obj[key], appendPath(path, key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		for _, key := range sortedKeys(obj) {
+			if err := checkKnownFields(t.Elem(), obj[key], appendPath(path, key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil
+		}
+
+		for i, item := range list {
+			if err := checkKnownFields(t.Elem(), item, appendPath(path, fmt.Sprint(i))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonField returns the field of the struct type with the json name.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if fieldName, ok := jsonFieldName(t.Field(i)); ok && fieldName == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
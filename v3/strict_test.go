@@ -0,0 +1,50 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_fromJsonStrict(t *testing.T) {
+	clean := `{"openapi":"3.0.3","info":{"title":"api","version":"1.0","x-logo":"logo.png"},"paths":{"/pets":{"get":{"responses":{"200":{"description":"ok","content":{"application/json":{"schema":{"type":"array","items":{"type":"string"},"additionalProperties":false}}}}}}}}}`
+	doc, err := FromJsonStrict([]byte(clean))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Paths.Item("/pets").Get == nil {
+		t.Fatal("expected the get operation")
+	}
+
+	typo := strings.Replace(clean, `"responses"`, `"respones"`, 1)
+	if _, err := FromJson([]byte(typo)); err != nil {
+		t.Fatalf("expected the lenient parser to ignore the typo: %v", err)
+	}
+
+	_, err = FromJsonStrict([]byte(typo))
+	if err == nil || !strings.Contains(err.Error(), "unknown field 'respones' at #/paths/~1pets/get/respones") {
+		t.Fatalf("expected an unknown field error but got %v", err)
+	}
+
+	nested := strings.Replace(clean, `"items":{"type":"string"}`, `"items":{"typ":"string"}`, 1)
+	_, err = FromJsonStrict([]byte(nested))
+	if err == nil || !strings.Contains(err.Error(), "#/paths/~1pets/get/responses/200/content/application~1json/schema/items/typ") {
+		t.Fatalf("expected an unknown field error in items but got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+export type Animal = Pet | (string | null);
+
+export interface Owner {
+  address?: { city?: string };
+  name: string;
+}
+
+/**
+ * A Pet is an animal,
+ * which lives with its owner.
+ */
+export interface Pet {
+  birthday?: string | null;
+  id: number;
+  name: string;
+  owner?: Owner;
+  status?: "available" | "sold";
+  tags?: string[];
+  /**
+   * the rating
+   */
+  "x-rating"?: number;
+}
+
+export type Pets = Pet[];
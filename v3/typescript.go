@@ -0,0 +1,232 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tsIdentifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// GenerateTypeScript emits a TypeScript declaration for each component schema in sorted order. Object schemas
+// become interfaces, where properties, which are not required, are optional, and all other schemas become type
+// aliases. Integers and numbers map to number, formats like date-time are kept as string, oneOf and anyOf
+// become unions, allOf becomes an intersection and a nullable type is a union with null. References to
+// component schemas are emitted by the name of the component. Descriptions become doc comments.
+func (d *Document) GenerateTypeScript() (string, error) {
+	var schemas map[string]Schema
+	if d.Components != nil {
+		schemas = d.Components.Schemas
+	}
+
+	sb := &strings.Builder{}
+	for i, name := range sortedKeys(schemas) {
+		if !tsIdentifierRegex.MatchString(name) {
+			return "", fmt.Errorf("'%s' is not a valid TypeScript identifier", name)
+		}
+
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		schema := schemas[name]
+		writeTsComment(sb, "", schema.Description)
+		if isTsInterface(schema) {
+			sb.WriteString("export interface " + name + " {\n")
+			for _, prop := range sortedKeys(schema.Properties) {
+				t, err := tsType(schema.Properties[prop])
+				if err != nil {
+					return "", fmt.Errorf("%s.%s: %w", name, prop, err)
+				}
+
+				writeTsComment(sb, "  ", schema.Properties[prop].Description)
+				sb.WriteString("  " + tsPropertyName(prop, schema.Required) + ": " + t + ";\n")
+			}
+			sb.WriteString("}\n")
+			continue
+		}
+
+		t, err := tsType(schema)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		sb.WriteString("export type " + name + " = " + t + ";\n")
+	}
+	return sb.String(), nil
+}
+
+// isTsInterface returns true for a plain object schema, which is not nullable and has no composition.
+func isTsInterface(s Schema) bool {
+	return s.Type == Object && len(s.Types) == 0 && !s.Nullable && len(s.Properties) > 0 && s.AdditionalProperties == nil &&
+		len(s.AllOf) == 0 && len(s.OneOf) == 0 && len(s.AnyOf) == 0 && len(s.Enum) == 0 && s.Ref == nil
+}
+
+// tsType returns the inline TypeScript type of the schema.
+func tsType(s Schema) (string, error) {
+	if s.Ref != nil {
+		if !strings.HasPrefix(*s.Ref, schemasPrefix) {
+			return "", &RefError{Ref: *s.Ref, Reason: UnsupportedRefPrefix}
+		}
+		return unescapePointerToken((*s.Ref)[len(schemasPrefix):]), nil
+	}
+
+	var t string
+	var err error
+	switch {
+	case len(s.OneOf) > 0:
+		t, err = tsTypes(s.OneOf, " | ")
+	case len(s.AnyOf) > 0:
+		t, err = tsTypes(s.AnyOf, " | ")
+	case len(s.AllOf) > 0:
+		t, err = tsTypes(s.AllOf, " & ")
+	case len(s.Enum) > 0:
+		var literals []string
+		for _, v := range s.Enum {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			literals = append(literals, string(b))
+		}
+		t = strings.Join(literals, " | ")
+	default:
+		types := s.Types
+		if len(types) == 0 {
+			types = []Type{s.Type}
+		}
+
+		var alternatives []string
+		for _, typ := range types {
+			alternative, err := tsPrimitive(s, typ)
+			if err != nil {
+				return "", err
+			}
+			alternatives = append(alternatives, alternative)
+		}
+		t = strings.Join(alternatives, " | ")
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if s.Nullable && !strings.HasSuffix(t, " | null") {
+		t += " | null"
+	}
+	return t, nil
+}
+
+// tsTypes joins the types of the schemas with the operator.
+func tsTypes(schemas []Schema, operator string) (string, error) {
+	var types []string
+	for _, s := range schemas {
+		t, err := tsType(s)
+		if err != nil {
+			return "", err
+		}
+
+		if strings.Contains(t, " ") && !strings.HasPrefix(t, "{") {
+			t = "(" + t + ")"
+		}
+		types = append(types, t)
+	}
+	return strings.Join(types, operator), nil
+}
+
+// tsPrimitive returns the TypeScript type of the single type of the schema.
+func tsPrimitive(s Schema, t Type) (string, error) {
+	switch t {
+	case String:
+		return "string", nil
+	case Integer, Number:
+		return "number", nil
+	case Boolean:
+		return "boolean", nil
+	case Null:
+		return "null", nil
+	case Array:
+		item := "unknown"
+		if s.Items != nil && s.Items.Schema != nil {
+			var err error
+			if item, err = tsType(*s.Items.Schema); err != nil {
+				return "", err
+			}
+		}
+
+		if strings.Contains(item, " ") {
+			return "Array<" + item + ">", nil
+		}
+		return item + "[]", nil
+	case Object, "":
+		if len(s.Properties) == 0 {
+			if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+				value, err := tsType(*s.AdditionalProperties.Schema)
+				if err != nil {
+					return "", err
+				}
+				return "Record<string, " + value + ">", nil
+			}
+
+			if t == "" {
+				return "unknown", nil
+			}
+			return "Record<string, unknown>", nil
+		}
+
+		var props []string
+		for _, name := range sortedKeys(s.Properties) {
+			prop, err := tsType(s.Properties[name])
+			if err != nil {
+				return "", err
+			}
+			props = append(props, tsPropertyName(name, s.Required)+": "+prop)
+		}
+		return "{ " + strings.Join(props, "; ") + " }", nil
+	default:
+		return "", fmt.Errorf("unsupported type '%s'", t)
+	}
+}
+
+// tsPropertyName quotes names, which are no identifiers, and marks properties, which are not required, as optional.
+func tsPropertyName(name string, required []string) string {
+	optional := ""
+	if !containsString(required, name) {
+		optional = "?"
+	}
+
+	if !tsIdentifierRegex.MatchString(name) {
+		b, _ := json.Marshal(name)
+		name = string(b)
+	}
+	return name + optional
+}
+
+// writeTsComment writes the description as doc comment, if not empty.
+func writeTsComment(sb *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+
+	sb.WriteString(indent + "/**\n")
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		sb.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	sb.WriteString(indent + " */\n")
+}
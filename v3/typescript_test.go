@@ -0,0 +1,65 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func Test_generateTypeScript(t *testing.T) {
+	owner, pet := "#/components/schemas/Owner", "#/components/schemas/Pet"
+	doc := NewDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Pet": {
+			Type:        Object,
+			Description: "A Pet is an animal,\nwhich lives with its owner.",
+			Required:    []string{"id", "name"},
+			Properties: map[string]Schema{
+				"id":       {Type: Integer, Format: string(Int64)},
+				"name":     {Type: String},
+				"birthday": {Type: String, Format: string(DateTime), Nullable: true},
+				"owner":    {Ref: &owner},
+				"tags":     {Type: Array, Items: &Items{&Schema{Type: String}}},
+				"status":   {Type: String, Enum: []interface{}{"available", "sold"}},
+				"x-rating": {Type: Number, Description: "the rating"},
+			},
+		},
+		"Owner": {
+			Type:       Object,
+			Required:   []string{"name"},
+			Properties: map[string]Schema{"name": {Type: String}, "address": {Type: Object, Properties: map[string]Schema{"city": {Type: String}}}},
+		},
+		"Pets":   {Type: Array, Items: &Items{&Schema{Ref: &pet}}},
+		"Animal": {OneOf: []Schema{{Ref: &pet}, {Type: String, Nullable: true}}},
+	}}
+
+	ts, err := doc.GenerateTypeScript()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "typescript", "pets.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ts != string(golden) {
+		t.Fatalf("expected\n%s\nbut got\n%s", golden, ts)
+	}
+}
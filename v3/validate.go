@@ -0,0 +1,349 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var statusCodeRegex = regexp.MustCompile(`^([1-5][0-9][0-9]|[1-5]XX)$`)
+
+// Validate checks the structural invariants of the specification and returns all violations. An empty result
+// means that no violation has been found, which does not necessarily imply that the document is valid.
+func (d *Document) Validate() []error {
+	var errs []error
+	if d.OpenAPI == "" {
+		errs = append(errs, fmt.Errorf("#/openapi: version is required"))
+	}
+
+	if d.Info.Title == "" {
+		errs = append(errs, fmt.Errorf("#/info/title: title is required"))
+	}
+
+	if d.Info.Version == "" {
+		errs = append(errs, fmt.Errorf("#/info/version: version is required"))
+	}
+
+	if d.Info.License != nil && d.Info.License.Identifier != "" && d.Info.License.Url.URL != nil {
+		errs = append(errs, fmt.Errorf("#/info/license: identifier and url are mutually exclusive"))
+	}
+
+	for _, path := range sortedKeys(d.Paths) {
+		item, _ := d.Paths.Get(path)
+		errs = append(errs, item.validate(pointerOf("paths", path))...)
+	}
+
+	if d.Is31() && d.Paths.Len() == 0 && len(d.Webhooks) == 0 && d.Components == nil {
+		errs = append(errs, fmt.Errorf("#: at least one of paths, webhooks or components is required"))
+	}
+
+	if len(d.Webhooks) > 0 && !d.Is31() {
+		errs = append(errs, fmt.Errorf("#/webhooks: webhooks require OpenAPI 3.1"))
+	}
+
+	for _, name := range sortedKeys(d.Webhooks) {
+		item := d.Webhooks[name]
+		errs = append(errs, item.validate(pointerOf("webhooks", name))...)
+	}
+
+	errs = append(errs, d.ValidatePathParameters()...)
+
+	return errs
+}
+
+// validate checks the parameters and operations of the path item, which is located at loc.
+func (p *PathItem) validate(loc string) []error {
+	errs := validateParameters(pointerOf(loc, "parameters"), p.Parameters)
+	for _, method := range sortedMethods(*p) {
+		op := p.Map()[method]
+		errs = append(errs, op.validate(pointerOf(loc, strings.ToLower(method)))...)
+	}
+	return errs
+}
+
+// validate checks the operation, which is located at loc.
+func (o *Operation) validate(loc string) []error {
+	var errs []error
+	if len(o.Responses) == 0 {
+		errs = append(errs, fmt.Errorf("%s/responses: at least one response is required", loc))
+	}
+
+	errs = append(errs, validateParameters(pointerOf(loc, "parameters"), o.Parameters)...)
+
+	for _, code := range sortedKeys(o.Responses) {
+		if code != "default" && !statusCodeRegex.MatchString(code) {
+			errs = append(errs, fmt.Errorf("%s: '%s' is not a valid status code", pointerOf(loc, "responses", code), code))
+		}
+
+		for _, contentType := range sortedKeys(o.Responses[code].Content) {
+			if err := o.Responses[code].Content[contentType].Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", pointerOf(loc, "responses", code, "content", contentType), err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateParameters checks each parameter of the list, which is located at loc.
+func validateParameters(loc string, params []Parameter) []error {
+	var errs []error
+	for i, p := range params {
+		if err := p.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pointerOf(loc, strconv.Itoa(i)), err))
+		}
+	}
+	return errs
+}
+
+// ValidatePathParameters cross-checks the {name} segments of each path template against the declared path
+// parameters of the path items and their operations. It reports segments without a declared and required
+// parameter as well as declared path parameters, which do not appear in the template.
+func (d *Document) ValidatePathParameters() []error {
+	var errs []error
+	for _, path := range sortedKeys(d.Paths) {
+		item, _ := d.Paths.Get(path)
+		item.Parameters = d.resolveParameters(item.Parameters)
+		names := pathTemplateParams(path)
+
+		errs = append(errs, orphanedPathParameters(pointerOf("paths", path, "parameters"), names, item.Parameters)...)
+		for _, method := range sortedMethods(item) {
+			op := *item.Map()[method]
+			op.Parameters = d.resolveParameters(op.Parameters)
+			loc := pointerOf("paths", path, strings.ToLower(method), "parameters")
+
+			errs = append(errs, orphanedPathParameters(loc, names, op.Parameters)...)
+			params := op.EffectiveParameters(&item)
+			for _, name := range names {
+				declared := false
+				for _, p := range params {
+					if p.Name == name && p.In == PathLocation {
+						declared = true
+						if !p.Required {
+							errs = append(errs, fmt.Errorf("%s: path parameter '%s' must be required", loc, name))
+						}
+					}
+				}
+
+				if !declared {
+					errs = append(errs, fmt.Errorf("%s: path parameter '%s' is not declared", loc, name))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// orphanedPathParameters reports each declared path parameter, whose name is not contained in names.
+func orphanedPathParameters(loc string, names []string, params []Parameter) []error {
+	var errs []error
+	for _, p := range params {
+		if p.In != PathLocation {
+			continue
+		}
+
+		found := false
+		for _, name := range names {
+			if p.Name == name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			errs = append(errs, fmt.Errorf("%s: path parameter '%s' does not appear in the path", loc, p.Name))
+		}
+	}
+	return errs
+}
+
+// ValidateExamples checks the example and examples of each schema, media type and parameter against the schema
+// and returns all violations. The type, enum, required properties and numeric bounds are checked recursively and
+// references are resolved. A parameter, which declares its content instead of a schema, is checked against the
+// schemas of its media types. The examples of a referenced parameter are reported once at the component.
+// Examples given as external values are ignored.
+func (d *Document) ValidateExamples() []error {
+	var errs []error
+	seen := map[string]bool{}
+	_ = d.Walk(func(path []string, node interface{}) error {
+		var schemas []Schema
+		var examples []exampleValue
+		switch n := node.(type) {
+		case Schema:
+			schemas = []Schema{n}
+			examples = collectExamples(path, n.Example, nil)
+		case MediaType:
+			schemas = []Schema{n.Schema}
+			examples = collectExamples(path, n.Example, n.Examples)
+		case Parameter:
+			if n.Ref != nil {
+				name, resolved := d.ResolveParameterRef(*n.Ref)
+				if resolved == nil {
+					return nil
+				}
+				n, path = *resolved, []string{"components", "parameters", name}
+			}
+
+			schemas = parameterSchemas(n)
+			examples = collectExamples(path, n.Example, n.Examples)
+		}
+
+		for _, example := range examples {
+			if seen[example.pointer] {
+				continue
+			}
+			seen[example.pointer] = true
+
+			value, err := normalizeJson(example.value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", example.pointer, err))
+				continue
+			}
+
+			for i := range schemas {
+				errs = append(errs, d.validateJsonValue(example.pointer, &schemas[i], value)...)
+			}
+		}
+		return nil
+	})
+	return errs
+}
+
+// parameterSchemas returns the schema of the parameter or, if it declares its content instead, the schemas of its
+// media types in the order of their names.
+func parameterSchemas(p Parameter) []Schema {
+	if p.hasSchema() || len(p.Content) == 0 {
+		return []Schema{p.Schema}
+	}
+
+	var r []Schema
+	for _, key := range sortedKeys(p.Content) {
+		r = append(r, p.Content[key].Schema)
+	}
+	return r
+}
+
+// exampleValue is an inline example and its location.
+type exampleValue struct {
+	pointer string
+	value   interface{}
+}
+
+// collectExamples returns the example and the inline values of the examples in the order of their names.
+func collectExamples(path []string, example interface{}, examples map[string]Example) []exampleValue {
+	var r []exampleValue
+	if example != nil {
+		r = append(r, exampleValue{pointer: pointerOf(append(append([]string{}, path...), "example")...), value: example})
+	}
+
+	for _, name := range sortedKeys(examples) {
+		if value := examples[name].Value; value != nil {
+			tokens := append(append([]string{}, path...), "examples", name, "value")
+			r = append(r, exampleValue{pointer: pointerOf(tokens...), value: value})
+		}
+	}
+	return r
+}
+
+// normalizeJson converts a go value into its generic json representation, e.g. an int into a float64.
+func normalizeJson(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var r interface{}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// resolveParameters returns a copy of the parameters, where each resolvable reference has been replaced.
+func (d *Document) resolveParameters(params []Parameter) []Parameter {
+	var r []Parameter
+	for _, p := range params {
+		if p.Ref != nil {
+			if _, resolved := d.ResolveParameterRef(*p.Ref); resolved != nil {
+				p = *resolved
+			}
+		}
+		r = append(r, p)
+	}
+	return r
+}
+
+// pathTemplateParams returns the names of the {name} segments of the path template.
+func pathTemplateParams(path string) []string {
+	var names []string
+	for {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			return names
+		}
+		end := strings.Index(path[start:], "}")
+		if end < 0 {
+			return names
+		}
+		names = append(names, path[start+1:start+end])
+		path = path[start+end+1:]
+	}
+}
+
+// pointerOf escapes and joins the tokens to a json pointer. If the first token is already a pointer,
+// it is not escaped.
+func pointerOf(tokens ...string) string {
+	sb := &strings.Builder{}
+	for i, token := range tokens {
+		if i == 0 && strings.HasPrefix(token, "#") {
+			sb.WriteString(token)
+			continue
+		}
+		if i == 0 {
+			sb.WriteString("#")
+		}
+		sb.WriteString("/")
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// sortedMethods returns the declared http methods of the path item in a stable order.
+func sortedMethods(item PathItem) []string {
+	return sortedKeys(item.Map())
+}
+
+// sortedKeys returns the keys of a map with string keys in a stable order.
+func sortedKeys(m interface{}) []string {
+	if paths, ok := m.(Paths); ok {
+		keys := paths.Keys()
+		sort.Strings(keys)
+		return keys
+	}
+
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"strings"
+	"testing"
+)
+
+func validDocument() *Document {
+	doc := NewDocument()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1"}
+	doc.Paths.Set("/pets/{id}", PathItem{
+		Parameters: []Parameter{{Name: "id", In: PathLocation, Required: true, Schema: Schema{Type: Integer}}},
+		Get: &Operation{
+			Responses: map[string]Response{"200": {Description: "ok"}, "4XX": {Description: "client error"}, "default": {Description: "error"}},
+		},
+	})
+	return doc
+}
+
+// assertViolation fails, if not exactly one error has been found, which contains the given text.
+func assertViolation(t *testing.T, errs []error, text string) {
+	t.Helper()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), text) {
+		t.Fatalf("expected a single violation containing '%s' but got %v", text, errs)
+	}
+}
+
+func Test_validate(t *testing.T) {
+	if errs := validDocument().Validate(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	doc := validDocument()
+	doc.OpenAPI = ""
+	assertViolation(t, doc.Validate(), "#/openapi")
+
+	doc = validDocument()
+	doc.Info.Title = ""
+	assertViolation(t, doc.Validate(), "#/info/title")
+
+	doc = validDocument()
+	doc.Info.Version = ""
+	assertViolation(t, doc.Validate(), "#/info/version")
+
+	doc = validDocument()
+	doc.Paths.Item("/pets/{id}").Get.Responses = nil
+	assertViolation(t, doc.Validate(), "#/paths/~1pets~1{id}/get/responses: at least one response")
+
+	doc = validDocument()
+	doc.Paths.Item("/pets/{id}").Get.Responses["20"] = Response{Description: "invalid"}
+	assertViolation(t, doc.Validate(), "'20' is not a valid status code")
+
+	doc = validDocument()
+	doc.Paths.Set("/pets/{id}", PathItem{Get: doc.Paths.Item("/pets/{id}").Get})
+	assertViolation(t, doc.Validate(), "path parameter 'id' is not declared")
+
+	doc = validDocument()
+	doc.Paths.Item("/pets/{id}").Get.Parameters = []Parameter{{Name: "id", In: PathLocation, Schema: Schema{Type: Integer}}}
+	assertViolation(t, doc.Validate(), "path parameter 'id' must be required")
+
+	doc = validDocument()
+	doc.Info = Info{}
+	doc.Paths.Item("/pets/{id}").Get.Responses = nil
+	if errs := doc.Validate(); len(errs) != 3 {
+		t.Fatalf("expected all violations but got %v", errs)
+	}
+}
+
+func Test_validatePathParameters(t *testing.T) {
+	if errs := validDocument().ValidatePathParameters(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	doc := validDocument()
+	doc.Paths.Set("/pets/{id}", PathItem{Get: doc.Paths.Item("/pets/{id}").Get})
+	assertViolation(t, doc.ValidatePathParameters(), "#/paths/~1pets~1{id}/get/parameters: path parameter 'id' is not declared")
+
+	doc = validDocument()
+	doc.Paths.Item("/pets/{id}").Get.Parameters = []Parameter{{Name: "name", In: PathLocation, Required: true, Schema: Schema{Type: String}}}
+	assertViolation(t, doc.ValidatePathParameters(), "path parameter 'name' does not appear in the path")
+
+	ref := "#/components/parameters/id"
+	doc = validDocument()
+	doc.Components = &Components{Parameters: map[string]Parameter{"id": {Name: "id", In: PathLocation, Required: true}}}
+	doc.Paths.Set("/pets/{id}", PathItem{Parameters: []Parameter{{Ref: &ref}}, Get: doc.Paths.Item("/pets/{id}").Get})
+	if errs := doc.ValidatePathParameters(); len(errs) != 0 {
+		t.Fatalf("expected no violations for a referenced parameter but got %v", errs)
+	}
+}
+
+func Test_validateExamples(t *testing.T) {
+	pet := "#/components/schemas/Pet"
+	doc := validDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Pet": {
+			Type:       Object,
+			Required:   []string{"name"},
+			Properties: map[string]Schema{"name": {Type: String}, "age": {Type: Integer, Minimum: float64Ptr(0)}},
+			Example:    map[string]interface{}{"name": "Rex", "age": 3},
+		},
+	}}
+	doc.Paths.Item("/pets/{id}").Get.Responses["200"] = Response{Description: "ok", Content: map[string]MediaType{
+		"application/json": {
+			Schema:   Schema{Ref: &pet},
+			Examples: map[string]Example{"rex": {Value: map[string]interface{}{"name": "Rex"}}},
+		},
+	}}
+
+	if errs := doc.ValidateExamples(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	doc.Components.Schemas["Pet"] = Schema{
+		Type:       Object,
+		Required:   []string{"name"},
+		Properties: map[string]Schema{"name": {Type: String}, "age": {Type: Integer, Minimum: float64Ptr(0)}},
+		Example:    map[string]interface{}{"name": "Rex", "age": "three"},
+	}
+	assertViolation(t, doc.ValidateExamples(), "#/components/schemas/Pet/example.age: expected integer")
+
+	doc.Components.Schemas["Pet"] = Schema{Type: Object, Properties: map[string]Schema{"age": {Type: Integer, Minimum: float64Ptr(0)}}}
+	doc.Paths.Item("/pets/{id}").Get.Responses["200"].Content["application/json"] = MediaType{
+		Schema:   Schema{Ref: &pet},
+		Examples: map[string]Example{"young": {Value: map[string]interface{}{"age": -1}}},
+	}
+	assertViolation(t, doc.ValidateExamples(), "#/paths/~1pets~1{id}/get/responses/200/content/application~1json/examples/young/value.age: number -1 is less than the minimum 0")
+}
+
+func Test_validateExamplesOfParameters(t *testing.T) {
+	doc := validDocument()
+	get := doc.Paths.Item("/pets/{id}").Get
+	get.Parameters = []Parameter{{
+		Name:    "filter",
+		In:      QueryLocation,
+		Content: JSONContent(Schema{Type: Object, Required: []string{"name"}}),
+		Example: map[string]interface{}{"age": 3},
+	}}
+	assertViolation(t, doc.ValidateExamples(), "#/paths/~1pets~1{id}/get/parameters/0/example: missing required property 'name'")
+
+	limit := "#/components/parameters/limit"
+	get.Parameters = []Parameter{{Ref: &limit}}
+	doc.Components = &Components{Parameters: map[string]Parameter{
+		"limit": {Name: "limit", In: QueryLocation, Schema: Schema{Type: Integer}, Example: "ten"},
+	}}
+	assertViolation(t, doc.ValidateExamples(), "#/components/parameters/limit/example: expected integer")
+
+	doc.Components.Parameters["limit"] = Parameter{Name: "limit", In: QueryLocation, Schema: Schema{Type: Integer}, Example: 10}
+	if errs := doc.ValidateExamples(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+}
+
+func Test_validateLicense(t *testing.T) {
+	doc := validDocument()
+	doc.Info.License = &License{Name: "MIT", Identifier: "MIT"}
+	if errs := doc.Validate(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	doc.Info.License.Url = mustParse("https://opensource.org/licenses/MIT")
+	assertViolation(t, doc.Validate(), "#/info/license: identifier and url are mutually exclusive")
+}
+
+func Test_validateParameterExamples(t *testing.T) {
+	doc := validDocument()
+	doc.Paths.Item("/pets/{id}").Get.Parameters = []Parameter{{
+		Name:     "status",
+		In:       QueryLocation,
+		Schema:   Schema{Type: String},
+		Examples: map[string]Example{"available": {Value: "available"}, "sold": {Value: "sold"}},
+	}}
+
+	if errs := doc.Validate(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	doc.Paths.Item("/pets/{id}").Get.Parameters[0].Example = "available"
+	assertViolation(t, doc.Validate(), "#/paths/~1pets~1{id}/get/parameters/0: example and examples are mutually exclusive")
+}
+
+func Test_validateParameterContent(t *testing.T) {
+	doc := validDocument()
+	doc.Paths.Item("/pets/{id}").Get.Parameters = []Parameter{{
+		Name:    "filter",
+		In:      QueryLocation,
+		Content: JSONContent(Schema{Type: Object}),
+	}}
+
+	if errs := doc.Validate(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	doc.Paths.Item("/pets/{id}").Get.Parameters[0].Schema = Schema{Type: String}
+	assertViolation(t, doc.Validate(), "#/paths/~1pets~1{id}/get/parameters/0: schema and content are mutually exclusive")
+}
+
+func Test_validateParameterSchemaRequired(t *testing.T) {
+	doc := validDocument()
+	doc.Paths.Item("/pets/{id}").Get.Parameters = []Parameter{{Name: "filter", In: QueryLocation}}
+	assertViolation(t, doc.Validate(), "#/paths/~1pets~1{id}/get/parameters/0: either a schema or a content is required")
+
+	content := JSONContent(Schema{Type: Object})
+	content["application/xml"] = MediaType{Schema: Schema{Type: Object}}
+	doc.Paths.Item("/pets/{id}").Get.Parameters = []Parameter{{Name: "filter", In: QueryLocation, Content: content}}
+	assertViolation(t, doc.Validate(), "content must contain exactly one media type")
+}
+
+func Test_validateWebhooks(t *testing.T) {
+	doc := NewDocument31()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1"}
+	doc.Webhooks = map[string]PathItem{
+		"newPet": {Post: &Operation{
+			RequestBody: &RequestBody{Content: JSONContent(Schema{Type: Object})},
+			Responses:   map[string]Response{"200": {Description: "received"}},
+		}},
+	}
+
+	if errs := doc.Validate(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	parsed, err := FromJson([]byte(doc.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item, ok := parsed.Webhooks["newPet"]; !ok || item.Post == nil {
+		t.Fatalf("expected the webhook to round-trip but got %v", parsed.Webhooks)
+	}
+
+	doc.Webhooks["newPet"].Post.Responses = nil
+	assertViolation(t, doc.Validate(), "#/webhooks/newPet/post/responses: at least one response is required")
+
+	doc = validDocument()
+	doc.Webhooks = map[string]PathItem{"newPet": {}}
+	assertViolation(t, doc.Validate(), "#/webhooks: webhooks require OpenAPI 3.1")
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// ValidateValue checks a decoded value, e.g. from json.Unmarshal, against the constraints of the schema and
+// returns all violations. Currently only the const and enum, the numeric bounds, the string constraints pattern,
+// minLength and maxLength, the array constraints minItems, maxItems and uniqueItems and the object constraints
+// minProperties and maxProperties are checked. References are not resolved.
+func (s *Schema) ValidateValue(v interface{}) []error {
+	var errs []error
+	if len(s.Enum) > 0 && !containsJsonValue(s.Enum, v) {
+		errs = append(errs, fmt.Errorf("value %v is not one of %v", v, s.Enum))
+	}
+
+	if s.Const != nil && !containsJsonValue([]interface{}{s.Const}, v) {
+		errs = append(errs, fmt.Errorf("value %v is not one of %v", v, []interface{}{s.Const}))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		errs = append(errs, s.validateArray(rv)...)
+	case reflect.Map:
+		errs = append(errs, s.validateObject(rv)...)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		errs = append(errs, s.validateNumber(float64(rv.Int()))...)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		errs = append(errs, s.validateNumber(float64(rv.Uint()))...)
+	case reflect.Float32, reflect.Float64:
+		errs = append(errs, s.validateNumber(rv.Float())...)
+	case reflect.String:
+		errs = append(errs, s.ValidateString(rv.String())...)
+	}
+	return errs
+}
+
+// containsJsonValue compares the json representations, so that e.g. an int and a float64 of the same value are
+// equal.
+func containsJsonValue(values []interface{}, v interface{}) bool {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	for _, candidate := range values {
+		if b, err := json.Marshal(candidate); err == nil && bytes.Equal(b, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) validateNumber(v float64) []error {
+	var errs []error
+	if s.Minimum != nil && (v < *s.Minimum || (s.ExclusiveMinimum && v == *s.Minimum)) {
+		errs = append(errs, fmt.Errorf("number %v is less than the minimum %v", v, *s.Minimum))
+	}
+
+	if s.Maximum != nil && (v > *s.Maximum || (s.ExclusiveMaximum && v == *s.Maximum)) {
+		errs = append(errs, fmt.Errorf("number %v is greater than the maximum %v", v, *s.Maximum))
+	}
+
+	if s.MultipleOf != nil && *s.MultipleOf != 0 {
+		if q := v / *s.MultipleOf; q != math.Trunc(q) {
+			errs = append(errs, fmt.Errorf("number %v is not a multiple of %v", v, *s.MultipleOf))
+		}
+	}
+
+	return errs
+}
+
+// patterns caches the compiled regular expressions by their pattern.
+var patterns sync.Map
+
+// ValidateString checks the string against the pattern, the minLength and the maxLength of the schema and returns
+// each violation separately. The length is measured in bytes, like documented by the Schema fields, and not in
+// characters. The compiled pattern is cached, so that repeated validations do not compile it again.
+func (s *Schema) ValidateString(v string) []error {
+	var errs []error
+	if s.Pattern != "" {
+		regex, err := compilePattern(s.Pattern)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case !regex.MatchString(v):
+			errs = append(errs, fmt.Errorf("string '%s' does not match the pattern '%s'", v, s.Pattern))
+		}
+	}
+
+	if s.MinLength > 0 && len(v) < s.MinLength {
+		errs = append(errs, fmt.Errorf("string has %d bytes but requires at least %d", len(v), s.MinLength))
+	}
+
+	if s.MaxLength > 0 && len(v) > s.MaxLength {
+		errs = append(errs, fmt.Errorf("string has %d bytes but allows at most %d", len(v), s.MaxLength))
+	}
+
+	return errs
+}
+
+// CompilePattern returns the compiled pattern of the schema, which is cached for subsequent calls. The pattern is
+// compiled by the go regexp package, which does not support all ECMA-262 features like lookarounds or
+// backreferences. It returns nil, if the schema has no pattern.
+func (s *Schema) CompilePattern() (*regexp.Regexp, error) {
+	if s.Pattern == "" {
+		return nil, nil
+	}
+	return compilePattern(s.Pattern)
+}
+
+// ValidatePatterns returns an error for each schema of the document with a pattern, which cannot be compiled.
+func (d *Document) ValidatePatterns() []error {
+	var errs []error
+	_ = d.Walk(func(path []string, node interface{}) error {
+		if schema, ok := node.(Schema); ok {
+			if _, err := schema.CompilePattern(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", pointerOf(appendPath(path, "pattern")...), err))
+			}
+		}
+		return nil
+	})
+	return errs
+}
+
+// compilePattern returns the cached regular expression or compiles and caches it.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if regex, ok := patterns.Load(pattern); ok {
+		return regex.(*regexp.Regexp), nil
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %w (ECMA-262 lookarounds and backreferences are not supported)", pattern, err)
+	}
+
+	patterns.Store(pattern, regex)
+	return regex, nil
+}
+
+func (s *Schema) validateObject(v reflect.Value) []error {
+	var errs []error
+	if s.MinProperties != nil && v.Len() < *s.MinProperties {
+		errs = append(errs, fmt.Errorf("object has %d properties but requires at least %d", v.Len(), *s.MinProperties))
+	}
+
+	if s.MaxProperties != nil && v.Len() > *s.MaxProperties {
+		errs = append(errs, fmt.Errorf("object has %d properties but allows at most %d", v.Len(), *s.MaxProperties))
+	}
+
+	return errs
+}
+
+func (s *Schema) validateArray(v reflect.Value) []error {
+	var errs []error
+	if s.MinItems > 0 && v.Len() < s.MinItems {
+		errs = append(errs, fmt.Errorf("array has %d items but requires at least %d", v.Len(), s.MinItems))
+	}
+
+	if s.MaxItems > 0 && v.Len() > s.MaxItems {
+		errs = append(errs, fmt.Errorf("array has %d items but allows at most %d", v.Len(), s.MaxItems))
+	}
+
+	if s.UniqueItems {
+		for i := 0; i < v.Len(); i++ {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(v.Index(i).Interface(), v.Index(j).Interface()) {
+					errs = append(errs, fmt.Errorf("array items %d and %d are not unique", j, i))
+				}
+			}
+		}
+	}
+
+	return errs
+}
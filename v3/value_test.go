@@ -0,0 +1,191 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_validateArray(t *testing.T) {
+	schema := Schema{Type: Array, MinItems: 2, MaxItems: 3, UniqueItems: true}
+
+	if errs := schema.ValidateValue([]interface{}{"a", "b"}); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue([]interface{}{"a", "b", "a"}); len(errs) != 1 {
+		t.Fatalf("expected a duplicate violation but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue([]interface{}{"a"}); len(errs) != 1 {
+		t.Fatalf("expected a min items violation but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue([]interface{}{1, 2, 3, 3}); len(errs) != 2 {
+		t.Fatalf("expected a max items and a duplicate violation but got %v", errs)
+	}
+}
+
+func Test_validateObject(t *testing.T) {
+	one, two := 1, 2
+	schema := Schema{Type: Object, MinProperties: &one, MaxProperties: &two}
+
+	if errs := schema.ValidateValue(map[string]interface{}{"a": 1}); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue(map[string]interface{}{}); len(errs) != 1 {
+		t.Fatalf("expected a min properties violation but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue(map[string]interface{}{"a": 1, "b": 2, "c": 3}); len(errs) != 1 {
+		t.Fatalf("expected a max properties violation but got %v", errs)
+	}
+}
+
+func Test_validateNumber(t *testing.T) {
+	schema := Schema{Type: Integer, Minimum: float64Ptr(1), Maximum: float64Ptr(10), ExclusiveMaximum: true, MultipleOf: float64Ptr(2)}
+
+	if errs := schema.ValidateValue(4); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue(float64(0)); len(errs) != 1 {
+		t.Fatalf("expected a minimum violation but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue(10); len(errs) != 1 {
+		t.Fatalf("expected an exclusive maximum violation but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue(3); len(errs) != 1 {
+		t.Fatalf("expected a multiple of violation but got %v", errs)
+	}
+}
+
+func Test_validateEnum(t *testing.T) {
+	schema := Schema{Type: String, Enum: []interface{}{"cat", "dog", 1}}
+
+	if errs := schema.ValidateValue("dog"); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue(float64(1)); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue("bird"); len(errs) != 1 {
+		t.Fatalf("expected an enum violation but got %v", errs)
+	}
+}
+
+func Test_validateConst(t *testing.T) {
+	cat := Schema{
+		Type:          Object,
+		Required:      []string{"petType"},
+		Properties:    map[string]Schema{"petType": {Type: String, Const: "cat"}},
+		Discriminator: &Discriminator{PropertyName: "petType"},
+	}
+
+	petType := cat.Properties["petType"]
+	if errs := petType.ValidateValue("cat"); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	enum := Schema{Type: String, Enum: []interface{}{"cat"}}
+	constErrs, enumErrs := petType.ValidateValue("dog"), enum.ValidateValue("dog")
+	if len(constErrs) != 1 || len(enumErrs) != 1 || constErrs[0].Error() != enumErrs[0].Error() {
+		t.Fatalf("expected const and enum to be equivalent but got %v and %v", constErrs, enumErrs)
+	}
+
+	b, err := json.Marshal(Schema{Type: Boolean, Const: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"type":"boolean","const":false}` {
+		t.Fatalf("expected a false const to be kept but got %s", b)
+	}
+
+	if example, err := cat.GenerateExample(NewDocument()); err != nil || example.(map[string]interface{})["petType"] != "cat" {
+		t.Fatalf("expected the const as example but got %v", example)
+	}
+}
+
+func Test_validateString(t *testing.T) {
+	schema := Schema{Type: String, Pattern: `^[a-z]+$`, MinLength: 2, MaxLength: 4}
+
+	if errs := schema.ValidateString("rex"); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	if errs := schema.ValidateString("Rex"); len(errs) != 1 || errs[0].Error() != "string 'Rex' does not match the pattern '^[a-z]+$'" {
+		t.Fatalf("expected a pattern violation but got %v", errs)
+	}
+
+	if errs := schema.ValidateString("bello"); len(errs) != 1 || errs[0].Error() != "string has 5 bytes but allows at most 4" {
+		t.Fatalf("expected a max length violation but got %v", errs)
+	}
+
+	if errs := schema.ValidateValue("Bello"); len(errs) != 2 {
+		t.Fatalf("expected a pattern and a max length violation but got %v", errs)
+	}
+
+	// lengths are measured in bytes
+	if errs := (&Schema{MaxLength: 2}).ValidateString("äö"); len(errs) != 1 {
+		t.Fatalf("expected a max length violation but got %v", errs)
+	}
+}
+
+func Test_validatePatterns(t *testing.T) {
+	schema := Schema{Type: String, Pattern: `^\d{3}-\d{4}$`}
+	regex, err := schema.CompilePattern()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !regex.MatchString("555-1234") {
+		t.Fatalf("expected the pattern to match")
+	}
+
+	if cached, _ := schema.CompilePattern(); cached != regex {
+		t.Fatal("expected the cached pattern")
+	}
+
+	changed := schema
+	changed.Pattern = `^\d+$`
+	if regex, err := changed.CompilePattern(); err != nil || !regex.MatchString("42") {
+		t.Fatalf("expected the changed pattern to be compiled but got %v", err)
+	}
+
+	doc := validDocument()
+	doc.Components = &Components{Schemas: map[string]Schema{
+		"Phone": schema,
+		"Name":  {Type: String, Pattern: `^(?!admin).*$`},
+	}}
+
+	errs := doc.ValidatePatterns()
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "#/components/schemas/Name/pattern: invalid pattern '^(?!admin).*$'") {
+		t.Fatalf("expected a single invalid pattern but got %v", errs)
+	}
+
+	if !strings.Contains(errs[0].Error(), "ECMA-262") {
+		t.Fatalf("expected a note about ECMA-262 but got %v", errs[0])
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Is31 returns true, if the document declares an OpenAPI 3.1 version, which is aligned with JSON Schema.
+func (d *Document) Is31() bool {
+	return strings.HasPrefix(d.OpenAPI, "3.1")
+}
+
+// MarshalJSON emits the extensions and the nullable schemas according to the declared version. For 3.1, a
+// nullable type becomes a type array including null, e.g. ["string","null"]. For 3.0, a type array with a single
+// type besides null becomes a nullable type. The paths are required and always emitted for 3.0 but omitted for
+// 3.1, if empty.
+func (d Document) MarshalJSON() ([]byte, error) {
+	type document Document
+	convert := schemaTo30
+	if d.Is31() {
+		convert = schemaTo31
+	}
+
+	if d.needsConversion(convert) {
+		c := &copier{convert: convert}
+		v, err := c.copy(reflect.ValueOf(&d))
+		if err != nil {
+			return nil, err
+		}
+		d = *v.Interface().(*Document)
+	}
+
+	var b []byte
+	var err error
+	if d.Is31() && d.Paths.Len() == 0 {
+		b, err = json.Marshal(struct {
+			document
+			Paths *Paths `json:"paths,omitempty"`
+		}{document: document(d)})
+	} else {
+		b, err = json.Marshal(document(d))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(b, d.Extensions)
+}
+
+// UnmarshalJSON parses the fields and the extensions.
+func (d *Document) UnmarshalJSON(b []byte) error {
+	type document Document
+	if err := json.Unmarshal(b, (*document)(d)); err != nil {
+		return err
+	}
+
+	ext, err := parseExtensions(b, reflect.TypeOf(*d))
+	d.Extensions = ext
+	return err
+}
+
+var errConversionRequired = errors.New("conversion required")
+
+// needsConversion returns true, if at least one schema is changed by convert.
+func (d *Document) needsConversion(convert func(s *Schema)) bool {
+	err := d.Walk(func(path []string, node interface{}) error {
+		if schema, ok := node.(Schema); ok {
+			converted := schema
+			convert(&converted)
+			if !reflect.DeepEqual(schema, converted) {
+				return errConversionRequired
+			}
+		}
+		return nil
+	})
+	return err == errConversionRequired
+}
+
+// schemaTo31 replaces nullable by a type array. Nullable schemas without a type are not changed.
+func schemaTo31(s *Schema) {
+	if !s.Nullable {
+		return
+	}
+
+	types := s.Types
+	if len(types) == 0 {
+		if s.Type == "" {
+			return
+		}
+		types = []Type{s.Type}
+	}
+
+	for _, t := range types {
+		if t == Null {
+			return
+		}
+	}
+
+	s.Types = append(append([]Type{}, types...), Null)
+	s.Type = ""
+	s.Nullable = false
+}
+
+// schemaTo30 replaces a type array by a nullable type. Type arrays with multiple types besides null cannot be
+// expressed and are not changed.
+func schemaTo30(s *Schema) {
+	var types []Type
+	nullable := false
+	for _, t := range s.Types {
+		if t == Null {
+			nullable = true
+		} else {
+			types = append(types, t)
+		}
+	}
+
+	if len(types) > 1 || len(s.Types) == 0 {
+		return
+	}
+
+	s.Types = nil
+	if len(types) == 1 {
+		s.Type = types[0]
+	}
+	s.Nullable = s.Nullable || nullable
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_nullableVersions(t *testing.T) {
+	for _, doc := range []*Document{NewDocument(), NewDocument31()} {
+		doc.Info = Info{Title: "Demo API", Version: "0.0.1", License: &License{Name: "MIT", Url: mustParse("https://opensource.org/licenses/MIT")}}
+		doc.Components = &Components{Schemas: map[string]Schema{
+			"Name": {Type: String, Nullable: true},
+		}}
+
+		str := doc.String()
+		expected := `"Name":{"type":"string","nullable":true}`
+		if doc.Is31() {
+			expected = `"Name":{"type":["string","null"]}`
+		}
+		if !strings.Contains(str, expected) {
+			t.Fatalf("expected %s in\n%s", expected, str)
+		}
+
+		// the model itself is not changed
+		if schema := doc.Components.Schemas["Name"]; schema.Type != String || !schema.Nullable || schema.Types != nil {
+			t.Fatalf("unexpected schema: %+v", schema)
+		}
+	}
+}
+
+func Test_typeArray(t *testing.T) {
+	doc, err := FromJson([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Demo API", "version": "0.0.1"},
+		"paths": {},
+		"components": {"schemas": {"Name": {"type": ["string", "null"], "maxLength": 5}, "Id": {"type": "integer"}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schema := doc.Components.Schemas["Name"]; len(schema.Types) != 2 || schema.Types[1] != Null {
+		t.Fatalf("unexpected schema: %+v", schema)
+	}
+
+	b, err := json.Marshal(doc.Components.Schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"Id":{"type":"integer"},"Name":{"type":["string","null"],"maxLength":5}}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	doc.OpenAPI = "3.0.1"
+	doc.Info.License = &License{Name: "MIT", Url: mustParse("https://opensource.org/licenses/MIT")}
+	if str := doc.String(); !strings.Contains(str, `"Name":{"type":"string","maxLength":5,"nullable":true}`) {
+		t.Fatalf("expected a nullable string in\n%s", str)
+	}
+
+	b, err = json.Marshal(Schema{Type: String, Types: []Type{String, Null}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":["string","null"]}` {
+		t.Fatalf("expected the types to take precedence but got %s", b)
+	}
+}
+
+func Test_optionalPaths(t *testing.T) {
+	doc := NewDocument()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1"}
+	if !strings.Contains(doc.String(), `"paths":{}`) {
+		t.Fatalf("expected empty paths for 3.0 in %s", doc.String())
+	}
+
+	doc = NewDocument31()
+	doc.Info = Info{Title: "Demo API", Version: "0.0.1"}
+	if strings.Contains(doc.String(), `"paths"`) {
+		t.Fatalf("expected no empty paths for 3.1 in %s", doc.String())
+	}
+	assertViolation(t, doc.Validate(), "at least one of paths, webhooks or components is required")
+
+	doc.Webhooks = map[string]PathItem{"newPet": {Post: &Operation{Responses: map[string]Response{"200": {Description: "ok"}}}}}
+	if errs := doc.Validate(); len(errs) != 0 {
+		t.Fatalf("expected no violations but got %v", errs)
+	}
+
+	doc.Paths.Set("/pets", PathItem{})
+	if !strings.Contains(doc.String(), `"paths":{"/pets":{}}`) {
+		t.Fatalf("expected the paths for 3.1 in %s", doc.String())
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Walk traverses the document depth-first and calls visit for each model object, like the Document itself, an
+// Operation, a Parameter, a Response or a Schema, including nested schemas in properties, items or allOf. The
+// path contains the json pointer tokens of the node, e.g. [paths /pets get] and the node is a copy of the
+// model struct, so modifications have no effect. Map entries are visited in the order of their keys and paths
+// in their declaration order. The walk is aborted with the first error returned by visit.
+func (d *Document) Walk(visit func(path []string, node interface{}) error) error {
+	return walkValue(reflect.ValueOf(d), nil, visit)
+}
+
+func walkValue(v reflect.Value, path []string, visit func(path []string, node interface{}) error) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return walkValue(v.Elem(), path, visit)
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, key := range keys {
+			if err := walkValue(v.MapIndex(key), appendPath(path, key.String()), visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkValue(v.Index(i), appendPath(path, strconv.Itoa(i)), visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		switch t := v.Interface().(type) {
+		case URL:
+			return nil
+		case Items:
+			return walkValue(reflect.ValueOf(t.Schema), path, visit)
+		case AdditionalProperties:
+			return walkValue(reflect.ValueOf(t.Schema), path, visit)
+		case Paths:
+			for _, entry := range t.all() {
+				if err := walkValue(reflect.ValueOf(entry.Item), appendPath(path, entry.Path), visit); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if err := visit(path, v.Interface()); err != nil {
+			return err
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			name, ok := jsonFieldName(v.Type().Field(i))
+			if !ok {
+				continue
+			}
+			if err := walkValue(v.Field(i), appendPath(path, name), visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		// scalars and free-form values like examples are not visited
+		return nil
+	}
+}
+
+// Walk traverses the schema depth-first and calls visit for the schema itself and each nested schema in
+// properties, items, additionalProperties, allOf, anyOf, oneOf and not. The path is the json pointer relative
+// to the schema, e.g. #/properties/tags/items, and properties are visited in sorted order. References are not
+// followed. Properties are visited as copies of their map entries, so modifications only affect the other schemas.
+func (s *Schema) Walk(visit func(path string, sub *Schema)) {
+	s.walk("#", visit)
+}
+
+func (s *Schema) walk(path string, visit func(path string, sub *Schema)) {
+	visit(path, s)
+
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		prop.walk(pointerOf(path, "properties", name), visit)
+	}
+
+	if s.Items != nil && s.Items.Schema != nil {
+		s.Items.walk(pointerOf(path, "items"), visit)
+	}
+
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		s.AdditionalProperties.Schema.walk(pointerOf(path, "additionalProperties"), visit)
+	}
+
+	for i := range s.AllOf {
+		s.AllOf[i].walk(pointerOf(path, "allOf", strconv.Itoa(i)), visit)
+	}
+
+	for i := range s.AnyOf {
+		s.AnyOf[i].walk(pointerOf(path, "anyOf", strconv.Itoa(i)), visit)
+	}
+
+	for i := range s.OneOf {
+		s.OneOf[i].walk(pointerOf(path, "oneOf", strconv.Itoa(i)), visit)
+	}
+
+	if s.Not != nil {
+		s.Not.walk(pointerOf(path, "not"), visit)
+	}
+}
+
+// appendPath returns a new path, which does not share its backing array with the given one.
+func appendPath(path []string, token string) []string {
+	r := make([]string, len(path), len(path)+1)
+	copy(r, path)
+	return append(r, token)
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_walk(t *testing.T) {
+	doc := NewDocument()
+	doc.Paths.Set("/pets", PathItem{
+		Get: &Operation{
+			Parameters: []Parameter{{Name: "limit", In: QueryLocation, Schema: Schema{Type: Integer}}},
+			Responses: map[string]Response{
+				"200": {
+					Description: "ok",
+					Content: map[string]MediaType{
+						"application/json": {
+							Schema: Schema{
+								Type: Array,
+								Items: &Items{&Schema{
+									Type: Object,
+									Properties: map[string]Schema{
+										"id":   {Type: Integer},
+										"tags": {AllOf: []Schema{{Type: String}}},
+									},
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	var schemas []string
+	err := doc.Walk(func(path []string, node interface{}) error {
+		if _, ok := node.(Schema); ok {
+			schemas = append(schemas, strings.Join(path, "/"))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"paths//pets/get/parameters/0/schema",
+		"paths//pets/get/responses/200/content/application/json/schema",
+		"paths//pets/get/responses/200/content/application/json/schema/items",
+		"paths//pets/get/responses/200/content/application/json/schema/items/properties/id",
+		"paths//pets/get/responses/200/content/application/json/schema/items/properties/tags",
+		"paths//pets/get/responses/200/content/application/json/schema/items/properties/tags/allOf/0",
+	}
+	if strings.Join(schemas, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected\n%s\nbut got\n%s", strings.Join(expected, "\n"), strings.Join(schemas, "\n"))
+	}
+
+	stop := errors.New("stop")
+	visits := 0
+	err = doc.Walk(func(path []string, node interface{}) error {
+		visits++
+		if _, ok := node.(*Operation); ok {
+			t.Fatal("expected operations to be visited as values")
+		}
+		if _, ok := node.(Operation); ok {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || visits != 4 { // Document, Info, PathItem and Operation
+		t.Fatalf("expected the walk to be aborted at the operation but got %v after %d visits", err, visits)
+	}
+}
+
+func Test_schemaWalk(t *testing.T) {
+	pet := "#/components/schemas/Pet"
+	schema := Schema{
+		Type: Object,
+		Properties: map[string]Schema{
+			"name": {Type: String},
+			"tags": {Type: Array, Items: &Items{&Schema{Type: String}}},
+			"best": {Ref: &pet},
+			"meta": {Type: Object, AdditionalProperties: &AdditionalProperties{Schema: &Schema{OneOf: []Schema{{Type: String}, {Type: Integer}}}}},
+		},
+	}
+
+	var paths []string
+	schema.Walk(func(path string, sub *Schema) {
+		paths = append(paths, path)
+	})
+
+	expected := []string{
+		"#",
+		"#/properties/best",
+		"#/properties/meta",
+		"#/properties/meta/additionalProperties",
+		"#/properties/meta/additionalProperties/oneOf/0",
+		"#/properties/meta/additionalProperties/oneOf/1",
+		"#/properties/name",
+		"#/properties/tags",
+		"#/properties/tags/items",
+	}
+
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected %v but got %v", expected, paths)
+	}
+}
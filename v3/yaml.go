@@ -0,0 +1,308 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML emits the URL as a plain string scalar.
+func (u URL) MarshalYAML() (interface{}, error) {
+	return u.URL.String(), nil
+}
+
+// UnmarshalYAML parses the URL from a plain string scalar.
+func (u *URL) UnmarshalYAML(value *yaml.Node) error {
+	var str string
+	if err := value.Decode(&str); err != nil {
+		return err
+	}
+	r, err := url.Parse(str)
+	if err != nil {
+		return err
+	}
+	u.URL = r
+	return nil
+}
+
+// YAML returns the document in the YAML format. The document is serialized through its JSON representation,
+// so that the same field names and omitempty semantics apply and the declaration order of fields is kept.
+func (d *Document) YAML() ([]byte, error) {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON, so the JSON is a valid YAML node tree which only needs to be restyled
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(b, node); err != nil {
+		return nil, err
+	}
+	resetStyle(node)
+
+	return yaml.Marshal(node)
+}
+
+// resetStyle removes the flow and quoting styles, which have been inherited from the JSON representation. The
+// encoder still quotes those strings, which would otherwise be interpreted as numbers, booleans or null.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// FromYaml tries to parse the document from the YAML format. Anchors, aliases and merge keys are expanded, the
+// order of mapping keys is kept and the result is the same as FromJson would have returned for the equivalent JSON.
+func FromYaml(data []byte) (*Document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	node := &root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a yaml mapping as document root")
+	}
+
+	if _, ok := mappingPairs(node)["openapi"]; !ok {
+		return nil, fmt.Errorf("missing required field 'openapi'")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeJson(buf, node, reflect.TypeOf(Document{})); err != nil {
+		return nil, err
+	}
+
+	return FromJson(buf.Bytes())
+}
+
+// writeJson writes the yaml node as json and keeps the order of mapping keys. Keys which are not strings in
+// yaml, like a status code 200, are written as strings. The model type t, which may be nil for free-form values,
+// tells where a string is expected, so that a plain scalar like the version 1.0 keeps its source text instead of
+// becoming the number 1. Timestamps keep their source text as well, because json has no such type.
+func writeJson(buf *bytes.Buffer, node *yaml.Node, t reflect.Type) error {
+	t = yamlTargetType(t)
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJson(buf, node.Content[0], t)
+	case yaml.AliasNode:
+		return writeJson(buf, node.Alias, t)
+	case yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil && t.Kind() == reflect.Slice {
+			elem = t.Elem()
+		}
+
+		buf.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJson(buf, child, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		pairs := mappingPairs(node)
+		for i, key := range mappingKeys(node) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			b, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			buf.WriteByte(':')
+			if err := writeJson(buf, pairs[key], yamlChildType(t, key)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	default:
+		if node.Tag == "!!timestamp" || (t != nil && t.Kind() == reflect.String && node.Tag != "!!null") {
+			b, err := json.Marshal(node.Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			return nil
+		}
+
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+}
+
+// yamlTargetType returns the type, whose json representation is expected for a yaml node of the model type, or
+// nil for a free-form value.
+func yamlTargetType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case nil:
+		return nil
+	case reflect.TypeOf(URL{}):
+		return reflect.TypeOf("")
+	case reflect.TypeOf(Items{}), reflect.TypeOf(AdditionalProperties{}):
+		return reflect.TypeOf(Schema{})
+	case reflect.TypeOf(Paths{}):
+		return reflect.TypeOf(map[string]PathItem{})
+	}
+
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	return t
+}
+
+// yamlChildType returns the model type of the value of the mapping key or nil, if unknown like an extension.
+func yamlChildType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if field, ok := jsonField(t, key); ok {
+			return field.Type
+		}
+	case reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
+
+// mappingKeys returns the keys of the mapping in declaration order, including those of merged mappings, which
+// are not overridden.
+func mappingKeys(node *yaml.Node) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			for _, merged := range mergedMappings(value) {
+				for _, mergedKey := range mappingKeys(merged) {
+					if !seen[mergedKey] {
+						seen[mergedKey] = true
+						keys = append(keys, mergedKey)
+					}
+				}
+			}
+			continue
+		}
+
+		if !seen[key.Value] {
+			seen[key.Value] = true
+			keys = append(keys, key.Value)
+		}
+	}
+	return keys
+}
+
+// mappingPairs returns the values of the mapping by key, where explicit keys override merged ones.
+func mappingPairs(node *yaml.Node) map[string]*yaml.Node {
+	pairs := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			for _, merged := range mergedMappings(value) {
+				for mergedKey, mergedValue := range mappingPairs(merged) {
+					if _, ok := pairs[mergedKey]; !ok {
+						pairs[mergedKey] = mergedValue
+					}
+				}
+			}
+			continue
+		}
+
+		pairs[key.Value] = value
+	}
+	return pairs
+}
+
+// mergedMappings returns the mappings of a merge key value, which is a mapping, an alias or a sequence of those.
+func mergedMappings(node *yaml.Node) []*yaml.Node {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return mergedMappings(node.Alias)
+	case yaml.MappingNode:
+		return []*yaml.Node{node}
+	case yaml.SequenceNode:
+		var r []*yaml.Node
+		for _, child := range node.Content {
+			r = append(r, mergedMappings(child)...)
+		}
+		return r
+	default:
+		return nil
+	}
+}
+
+// jsonValue converts the generic yaml values into values which are compatible with json, e.g. a status code key
+// like 200 is not a string in yaml.
+func jsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			t[key] = jsonValue(value)
+		}
+		return t
+	case map[interface{}]interface{}:
+		r := make(map[string]interface{}, len(t))
+		for key, value := range t {
+			r[fmt.Sprint(key)] = jsonValue(value)
+		}
+		return r
+	case []interface{}:
+		for i, value := range t {
+			t[i] = jsonValue(value)
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2020 Torben Schinke
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func Test_yaml(t *testing.T) {
+	doc, err := FromJson([]byte(`{
+		"openapi": "3.0.1",
+		"info": {"title": "Demo API", "version": "0.0.1"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"tags": ["pets"],
+					"parameters": [{"name": "limit", "in": "query", "description": "", "schema": {"type": "integer"}}],
+					"responses": {"200": {"description": "ok"}, "default": {"description": "error"}}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonBuf, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	yamlBuf, err := doc.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(yamlBuf), "openapi: 3.0.1\ninfo:\n") {
+		t.Fatalf("unexpected yaml:\n%s", yamlBuf)
+	}
+	if !strings.Contains(string(yamlBuf), `"200":`) {
+		t.Fatalf("expected quoted status code:\n%s", yamlBuf)
+	}
+
+	var fromJson, fromYaml interface{}
+	if err := json.Unmarshal(jsonBuf, &fromJson); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(yamlBuf, &fromYaml); err != nil {
+		t.Fatal(err)
+	}
+
+	// normalize the yaml numbers and maps by passing them through json
+	b, err := json.Marshal(fromYaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYaml = nil
+	if err := json.Unmarshal(b, &fromYaml); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(fromJson, fromYaml) {
+		t.Fatalf("expected\n%v\nbut got\n%v", fromJson, fromYaml)
+	}
+}
+
+func Test_fromYaml(t *testing.T) {
+	fromYaml, err := FromYaml([]byte(`
+openapi: 3.0.1
+info:
+  title: Demo API
+  version: 0.0.1
+  termsOfService: https://github.com/torbenschinke
+paths:
+  /pets:
+    get:
+      responses:
+        200: &ok
+          description: ok
+  /dogs:
+    get:
+      responses:
+        200: *ok
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromJson, err := FromJson([]byte(`{
+		"openapi": "3.0.1",
+		"info": {"title": "Demo API", "version": "0.0.1", "termsOfService": "https://github.com/torbenschinke"},
+		"paths": {
+			"/pets": {"get": {"responses": {"200": {"description": "ok"}}}},
+			"/dogs": {"get": {"responses": {"200": {"description": "ok"}}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(fromJson, fromYaml) {
+		t.Fatalf("expected\n%+v\nbut got\n%+v", fromJson, fromYaml)
+	}
+
+	if fromYaml.Info.TermsOfService == nil || fromYaml.Info.TermsOfService.Host != "github.com" {
+		t.Fatalf("unexpected terms of service: %v", fromYaml.Info.TermsOfService)
+	}
+
+	if _, err := FromYaml([]byte("info:\n  title: Demo API\n")); err == nil || !strings.Contains(err.Error(), "openapi") {
+		t.Fatalf("expected missing openapi error but got %v", err)
+	}
+}
+
+func Test_fromYamlScalars(t *testing.T) {
+	doc, err := FromYaml([]byte(`
+openapi: 3.0.3
+info:
+  title: 2020
+  version: 1.0
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: string
+                format: date
+                maxLength: 10
+                example: 2020-01-01
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Info.Version != "1.0" || doc.Info.Title != "2020" {
+		t.Fatalf("expected the source text of the scalars but got %+v", doc.Info)
+	}
+
+	schema := doc.Paths.Item("/pets").Get.Responses["200"].Content["application/json"].Schema
+	if schema.Example != "2020-01-01" || schema.MaxLength != 10 {
+		t.Fatalf("unexpected schema %+v", schema)
+	}
+}